@@ -2,7 +2,29 @@ package main
 
 import (
 	"project-scaffold/internal/cli"
+	_ "project-scaffold/internal/plugin/audit"
 	_ "project-scaffold/internal/plugin/auth"
+	_ "project-scaffold/internal/plugin/cron"
+	_ "project-scaffold/internal/plugin/email"
+	_ "project-scaffold/internal/plugin/flags"
+	_ "project-scaffold/internal/plugin/graphql"
+	_ "project-scaffold/internal/plugin/grpc"
+	_ "project-scaffold/internal/plugin/idempotency"
+	_ "project-scaffold/internal/plugin/jobs"
+	_ "project-scaffold/internal/plugin/messaging"
+	_ "project-scaffold/internal/plugin/metrics"
+	_ "project-scaffold/internal/plugin/otel"
+	_ "project-scaffold/internal/plugin/outbox"
+	_ "project-scaffold/internal/plugin/problems"
+	_ "project-scaffold/internal/plugin/ratelimit"
+	_ "project-scaffold/internal/plugin/redis"
+	_ "project-scaffold/internal/plugin/securityheaders"
+	_ "project-scaffold/internal/plugin/sse"
+	_ "project-scaffold/internal/plugin/storage"
+	_ "project-scaffold/internal/plugin/tenancy"
+	_ "project-scaffold/internal/plugin/validation"
+	_ "project-scaffold/internal/plugin/webhooks"
+	_ "project-scaffold/internal/plugin/websocket"
 )
 
 func main() {
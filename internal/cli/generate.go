@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"project-scaffold/internal/openapi"
+	"project-scaffold/internal/project"
+	"project-scaffold/internal/resource"
+)
+
+var flagGenerateForce bool
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate code inside an existing scaffolded project",
+}
+
+var generateOpenAPICmd = &cobra.Command{
+	Use:   "openapi <spec-file>",
+	Short: "Generate models, handlers, validation and routes from an OpenAPI 3 document",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc, err := openapi.Load(args[0])
+		if err != nil {
+			color.New(color.FgRed).Fprintf(cmd.ErrOrStderr(), "Could not read OpenAPI document: %v\n", err)
+			return err
+		}
+		meta, err := project.ReadMeta(".")
+		if err != nil {
+			color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), err)
+			return err
+		}
+		if err := openapi.Generate(".", meta, doc, flagGenerateForce); err != nil {
+			color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), err)
+			return err
+		}
+		color.New(color.FgGreen).Fprintf(cmd.OutOrStdout(), "✔ Generated API code from %s.\n", args[0])
+		return nil
+	},
+}
+
+var generateResourceCmd = &cobra.Command{
+	Use:   "resource <Name> [field:type...]",
+	Short: "Generate a model, repository, service, handlers and routes for a resource",
+	Long: "Generate a full CRUD slice for a resource and register its routes.\n\n" +
+		"Fields are given as name:type; supported types are " + resource.TypeNames() + ".\n" +
+		"A field named id sets the primary key type (uuid, int or bigint); otherwise a uuid id is added.\n\n" +
+		"Example:\n  project-scaffold generate resource Order id:uuid total:decimal status:string",
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		res, err := resource.New(args[0], args[1:])
+		if err != nil {
+			color.New(color.FgRed).Fprintf(cmd.ErrOrStderr(), "Invalid resource: %v\n", err)
+			return err
+		}
+		meta, err := project.ReadMeta(".")
+		if err != nil {
+			color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), err)
+			return err
+		}
+		if err := resource.Generate(".", meta, res, flagGenerateForce); err != nil {
+			color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), err)
+			return err
+		}
+		color.New(color.FgGreen).Fprintf(cmd.OutOrStdout(), "✔ Generated %s resource at %s.\n", res.Name, res.Path)
+		if meta.Database != "mongodb" {
+			color.New(color.FgYellow).Fprintf(cmd.OutOrStdout(), "Apply the new migration in migrations/ before starting the server.\n")
+		}
+		return nil
+	},
+}
+
+var generateFromSchemaCmd = &cobra.Command{
+	Use:   "from-schema <schema-file>",
+	Short: "Generate read-only models, repositories and endpoints from an existing database schema",
+	Long: "Generate read-only list/get endpoints for every table in an existing schema.\n\n" +
+		"PostgreSQL and SQLite projects read SQL DDL (CREATE TABLE statements); MongoDB projects read a\n" +
+		"JSON Schema ($jsonSchema validator, db.getCollectionInfos() output, or a map of collection schemas).\n" +
+		"The schema is parsed offline; no database connection is needed.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		meta, err := project.ReadMeta(".")
+		if err != nil {
+			color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), err)
+			return err
+		}
+		schema, err := resource.LoadSchema(args[0], meta.Database)
+		if err != nil {
+			color.New(color.FgRed).Fprintf(cmd.ErrOrStderr(), "Could not read schema: %v\n", err)
+			return err
+		}
+		for _, w := range schema.Warnings {
+			color.New(color.FgYellow).Fprintf(cmd.ErrOrStderr(), "⚠ %s\n", w)
+		}
+		for _, res := range schema.Resources {
+			if err := resource.Generate(".", meta, res, flagGenerateForce); err != nil {
+				color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), err)
+				return err
+			}
+			color.New(color.FgGreen).Fprintf(cmd.OutOrStdout(), "✔ Generated %s (%s) at %s.\n", res.Name, res.Table, res.Path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	generateOpenAPICmd.Flags().BoolVar(&flagGenerateForce, "force", false, "Overwrite previously generated files")
+	generateResourceCmd.Flags().BoolVar(&flagGenerateForce, "force", false, "Overwrite previously generated files")
+	generateFromSchemaCmd.Flags().BoolVar(&flagGenerateForce, "force", false, "Overwrite previously generated files")
+	generateCmd.AddCommand(generateOpenAPICmd)
+	generateCmd.AddCommand(generateResourceCmd)
+	generateCmd.AddCommand(generateFromSchemaCmd)
+}
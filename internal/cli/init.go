@@ -14,7 +14,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"project-scaffold/internal/generator"
+	"project-scaffold/internal/openapi"
 	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
 )
 
 var (
@@ -24,6 +26,9 @@ var (
 	flagDocker     bool
 	flagNoDocker   bool
 	flagPlugins    string
+	flagFromOpenAPI string
+	flagBroker     string
+	flagGraphQLSample bool
 )
 
 var initCmd = &cobra.Command{
@@ -52,6 +57,16 @@ var initCmd = &cobra.Command{
 			return errors.New("cannot use --docker and --no-docker together")
 		}
 
+		var apiDoc *openapi.Document
+		if strings.TrimSpace(flagFromOpenAPI) != "" {
+			doc, err := openapi.Load(flagFromOpenAPI)
+			if err != nil {
+				color.New(color.FgRed).Fprintf(cmd.ErrOrStderr(), "Could not read OpenAPI document: %v\n", err)
+				return err
+			}
+			apiDoc = doc
+		}
+
 		stack := generator.Stack("")
 		db := generator.Database("")
 		nodeVariant := ""
@@ -77,6 +92,14 @@ var initCmd = &cobra.Command{
 				nodeVariant = "js"
 			}
 		}
+		broker := ""
+		if strings.TrimSpace(flagBroker) != "" {
+			b, err := generator.ParseBrokerKey(flagBroker)
+			if err != nil {
+				return err
+			}
+			broker = b
+		}
 		if flagDocker {
 			useDocker = true
 		} else if flagNoDocker {
@@ -209,6 +232,23 @@ var initCmd = &cobra.Command{
 			}
 		}
 
+		if broker == "" && hasPlugin(pluginsSelected, "messaging") && stdinIsTTY && !nonInteractive {
+			brokerAnswers := struct {
+				Broker string `survey:"broker"`
+			}{}
+			if err := survey.Ask([]*survey.Question{{
+				Name: "broker",
+				Prompt: &survey.Select{
+					Message: "Message broker:",
+					Options: generator.Brokers,
+					Default: generator.Brokers[0],
+				},
+			}}, &brokerAnswers); err != nil {
+				return err
+			}
+			broker = brokerAnswers.Broker
+		}
+
 		targetDir := filepath.Join(".", projectName)
 		if _, err := os.Stat(targetDir); err == nil {
 			color.New(color.FgRed).Fprintln(cmd.ErrOrStderr(), "Folder already exists. Please choose a different project name.")
@@ -219,12 +259,14 @@ var initCmd = &cobra.Command{
 		}
 
 		opts := generator.Options{
-			ProjectName: projectName,
-			Stack:       stack,
-			Database:    db,
-			UseDocker:   useDocker,
-			Plugins:     pluginsSelected,
-			NodeVariant: nodeVariant,
+			ProjectName:   projectName,
+			Stack:         stack,
+			Database:      db,
+			UseDocker:     useDocker,
+			Plugins:       pluginsSelected,
+			NodeVariant:   nodeVariant,
+			Broker:        broker,
+			GraphQLSample: flagGraphQLSample,
 		}
 
 		yellow := color.New(color.FgYellow)
@@ -240,6 +282,9 @@ var initCmd = &cobra.Command{
 		spin.Start()
 
 		err := generator.Generate(targetDir, opts)
+		if err == nil && apiDoc != nil {
+			err = generateFromOpenAPI(targetDir, apiDoc)
+		}
 		spin.Stop()
 		fmt.Fprintln(cmd.OutOrStdout())
 
@@ -258,11 +303,21 @@ var initCmd = &cobra.Command{
 	},
 }
 
+func generateFromOpenAPI(targetDir string, doc *openapi.Document) error {
+	meta, err := project.ReadMeta(targetDir)
+	if err != nil {
+		return err
+	}
+	return openapi.Generate(targetDir, meta, doc, false)
+}
+
 func friendlyInitError(err error) string {
 	msg := err.Error()
 	switch {
 	case strings.Contains(msg, "template not found"):
 		return "This stack + database combination is not available yet."
+	case strings.HasPrefix(msg, "openapi:"):
+		return "The project was created, but code could not be generated from the OpenAPI document: " + msg
 	default:
 		return "Something went wrong while generating the project. Run with the same options again or check your environment."
 	}
@@ -277,6 +332,9 @@ func printNextSteps(cmd *cobra.Command, opts generator.Options) {
 	switch opts.Stack {
 	case generator.StackGoGin:
 		fmt.Fprintln(out, "  go mod tidy")
+		if hasPlugin(opts.Plugins, "graphql") {
+			fmt.Fprintln(out, "  go generate ./graph")
+		}
 		fmt.Fprintln(out, "  go run ./cmd")
 	case generator.StackNodeExpress:
 		fmt.Fprintln(out, "  cp .env.example .env")
@@ -299,6 +357,15 @@ func parsePluginsFlag(s string) []string {
 	return out
 }
 
+func hasPlugin(plugins []string, name string) bool {
+	for _, p := range plugins {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	initCmd.Flags().StringVar(&flagStackKey, "stack", "", "Stack key: go-gin | node-express (optional)")
 	initCmd.Flags().StringVar(&flagDBKey, "db", "", "Database key: postgresql | mongodb | sqlite (optional)")
@@ -306,5 +373,8 @@ func init() {
 	initCmd.Flags().BoolVar(&flagDocker, "docker", false, "Generate Dockerfile and docker-compose.yml (skip prompt)")
 	initCmd.Flags().BoolVar(&flagNoDocker, "no-docker", false, "Do not generate Docker files (skip prompt)")
 	initCmd.Flags().StringVar(&flagPlugins, "plugins", "", "Comma-separated plugin names, e.g. auth (optional)")
+	initCmd.Flags().StringVar(&flagBroker, "broker", "", "Message broker: nats | kafka | rabbitmq (optional, only for the messaging plugin)")
+	initCmd.Flags().BoolVar(&flagGraphQLSample, "graphql-sample", false, "Add sample Author and Post resources, with REST routes and tables, behind the GraphQL schema (only for the graphql plugin)")
+	initCmd.Flags().StringVar(&flagFromOpenAPI, "from-openapi", "", "Generate models, handlers and routes from an OpenAPI 3 document (optional)")
 }
 
@@ -2,7 +2,6 @@ package generator
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -12,6 +11,7 @@ import (
 	"text/template"
 
 	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
 	"project-scaffold/internal/templates"
 )
 
@@ -54,12 +54,31 @@ func ParseDatabaseKey(key string) (Database, error) {
 	}
 }
 
+// Brokers the messaging plugin can generate code for.
+var Brokers = []string{"nats", "kafka", "rabbitmq"}
+
+func ParseBrokerKey(key string) (string, error) {
+	k := strings.ToLower(strings.TrimSpace(key))
+	for _, b := range Brokers {
+		if k == b {
+			return b, nil
+		}
+	}
+	return "", fmt.Errorf("invalid broker %q (use: %s)", key, strings.Join(Brokers, " | "))
+}
+
 type Options struct {	ProjectName  string
 	Stack        Stack
 	Database     Database
 	UseDocker    bool
 	Plugins      []string
 	NodeVariant  string
+	// Broker is the messaging plugin's broker; empty means the first of
+	// Brokers.
+	Broker string
+	// GraphQLSample makes the graphql plugin generate its sample Author and
+	// Post resources.
+	GraphQLSample bool
 }
 
 type templateData struct {
@@ -153,16 +172,27 @@ func Generate(targetDir string, opts Options) error {
 		return err
 	}
 
-	meta := scaffoldMeta{
+	broker := ""
+	for _, p := range opts.Plugins {
+		if p == "messaging" {
+			broker = opts.Broker
+			if broker == "" {
+				broker = Brokers[0]
+			}
+		}
+	}
+
+	meta := project.Meta{
 		Stack:    effectiveStack,
 		Database: dbKey,
 		Plugins:  opts.Plugins,
+		Broker:   broker,
 	}
-	if err := writeScaffoldMeta(targetDir, meta); err != nil {
+	if err := project.WriteMeta(targetDir, meta); err != nil {
 		return fmt.Errorf("write scaffold metadata: %w", err)
 	}
 
-	for _, name := range opts.Plugins {
+	for _, name := range plugin.Ordered(opts.Plugins) {
 		p := plugin.Get(name)
 		if p == nil {
 			return fmt.Errorf("plugin %q not found", name)
@@ -178,12 +208,14 @@ func Generate(targetDir string, opts Options) error {
 			continue
 		}
 		ctx := &plugin.Context{
-			ProjectName: opts.ProjectName,
-			StackKey:    effectiveStack,
-			Database:    dbKey,
-			UseDocker:   opts.UseDocker,
-			TargetDir:   targetDir,
-			Plugins:     opts.Plugins,
+			ProjectName:   opts.ProjectName,
+			StackKey:      effectiveStack,
+			Database:      dbKey,
+			UseDocker:     opts.UseDocker,
+			TargetDir:     targetDir,
+			Plugins:       opts.Plugins,
+			Broker:        broker,
+			GraphQLSample: opts.GraphQLSample,
 		}
 		if err := p.Apply(ctx); err != nil {
 			return fmt.Errorf("plugin %s: %w", name, err)
@@ -193,20 +225,6 @@ func Generate(targetDir string, opts Options) error {
 	return nil
 }
 
-type scaffoldMeta struct {
-	Stack    string   `json:"stack"`
-	Database string   `json:"database"`
-	Plugins  []string `json:"plugins"`
-}
-
-func writeScaffoldMeta(targetDir string, meta scaffoldMeta) error {
-	b, err := json.MarshalIndent(meta, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(filepath.Join(targetDir, ".scaffold.json"), b, 0o644)
-}
-
 func mapDotfiles(p string) string {
 	switch filepath.ToSlash(p) {
 	case "env.example":
@@ -0,0 +1,141 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are kept upper-case in Go identifiers, as golint suggests.
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URL": true, "URI": true, "UUID": true,
+}
+
+// Words splits an identifier such as "order_items", "orderItems",
+// "Order-Items" or "order items" into lower-case words.
+func Words(s string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r):
+			// Split "orderID" before "I" and "HTTPServer" before "S".
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))) {
+				flush()
+			}
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// Pascal returns the exported Go identifier form, e.g. "order_id" -> "OrderID".
+func Pascal(s string) string {
+	var b strings.Builder
+	for _, w := range Words(s) {
+		if up := strings.ToUpper(w); commonInitialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	out := b.String()
+	if out != "" && unicode.IsDigit(rune(out[0])) {
+		out = "X" + out
+	}
+	return out
+}
+
+// Camel returns the lower camel-case form, e.g. "order_id" -> "orderId".
+func Camel(s string) string {
+	words := Words(s)
+	if len(words) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(words[0])
+	for _, w := range words[1:] {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	out := b.String()
+	if unicode.IsDigit(rune(out[0])) {
+		out = "_" + out
+	}
+	return out
+}
+
+// Snake returns the snake_case form, e.g. "OrderItem" -> "order_item".
+func Snake(s string) string {
+	return strings.Join(Words(s), "_")
+}
+
+// Kebab returns the kebab-case form, e.g. "OrderItem" -> "order-item".
+func Kebab(s string) string {
+	return strings.Join(Words(s), "-")
+}
+
+// Plural returns a naive English plural of the last word in s, keeping the
+// original casing style of s.
+func Plural(s string) string {
+	if s == "" {
+		return s
+	}
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"),
+		strings.HasSuffix(lower, "z"), strings.HasSuffix(lower, "ch"),
+		strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
+
+// Singular undoes Plural for the common cases, e.g. "order_items" ->
+// "order_item". Words that do not look plural are returned unchanged.
+func Singular(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "zes"), strings.HasSuffix(lower, "ches"),
+		strings.HasSuffix(lower, "shes"), strings.HasSuffix(lower, "uses"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"),
+		strings.HasSuffix(lower, "is"):
+		return s
+	case strings.HasSuffix(lower, "s") && len(lower) > 1:
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}
+
+// GoCamel returns an unexported Go identifier, e.g. "order_id" -> "orderID".
+func GoCamel(s string) string {
+	p := Pascal(s)
+	if p == "" {
+		return p
+	}
+	words := Words(s)
+	if up := strings.ToUpper(words[0]); commonInitialisms[up] && strings.HasPrefix(p, up) {
+		return strings.ToLower(up) + p[len(up):]
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
@@ -0,0 +1,626 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"project-scaffold/internal/naming"
+)
+
+// API is the stack-independent view of a document that the templates render.
+type API struct {
+	ProjectName string
+	Title       string
+	Models      []*Model
+	Endpoints   []*Endpoint
+	UsesTime    bool
+	HasBodies   bool
+	// TypedParams is set when a Go stub parses an integer, number or
+	// boolean parameter, which needs the parsing helpers.
+	TypedParams bool
+	// TSImports lists the models referenced by request bodies and responses.
+	TSImports []string
+}
+
+type Model struct {
+	Name        string
+	Description string
+	Fields      []*Field
+	// Alias is set for non-object component schemas, which become named
+	// types instead of structs / interfaces.
+	Alias   *Field
+	Rules   string // JSON validation rules used by the Node validator
+	Ordinal int
+}
+
+type Field struct {
+	JSONName string
+	GoName   string
+	TSName   string
+	GoType   string
+	TSType   string
+	Required bool
+	GoTag    string
+}
+
+type Endpoint struct {
+	Name          string
+	HandlerName   string
+	Summary       string
+	Method        string
+	RoutePath     string
+	Path          string
+	PathParams    []*Param
+	QueryParams   []*Param
+	Body          string
+	BodyRequired  bool
+	Response      string
+	GoResponse    string
+	TSResponse    string
+	SuccessStatus int
+	// ParamRules is the JSON list of parameter checks for the Node
+	// validator, empty when nothing needs checking.
+	ParamRules string
+	// GoUnused silences unused variables in the generated Go stub.
+	GoUnused string
+}
+
+type Param struct {
+	Name     string
+	Var      string
+	GoVar    string
+	Required bool
+	// Type is "integer", "number" or "boolean"; other parameters stay
+	// strings.
+	Type string
+	// GoParse and Kind are the Go parser and the noun used in the 400
+	// message for typed parameters.
+	GoParse string
+	Kind    string
+}
+
+var paramTypes = map[string]struct{ parse, kind string }{
+	"integer": {"parseInt", "an integer"},
+	"number":  {"parseFloat", "a number"},
+	"boolean": {"strconv.ParseBool", "a boolean"},
+}
+
+// paramRule is the JSON shape of a parameter check for the Node validator.
+type paramRule struct {
+	In       string `json:"in"`
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+var (
+	pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+	jsIdent   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+)
+
+// Build resolves refs and inline schemas into the API view.
+func Build(doc *Document, projectName string) (*API, error) {
+	b := &builder{doc: doc, api: &API{ProjectName: projectName, Title: doc.Info.Title}, byName: map[string]*Model{}}
+	for _, ns := range doc.Components.Schemas {
+		if _, err := b.model(naming.Pascal(ns.Name), ns.Schema); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", ns.Name, err)
+		}
+	}
+
+	methods := []struct {
+		name string
+		op   func(PathItem) *Operation
+	}{
+		{http.MethodGet, func(p PathItem) *Operation { return p.Get }},
+		{http.MethodPost, func(p PathItem) *Operation { return p.Post }},
+		{http.MethodPut, func(p PathItem) *Operation { return p.Put }},
+		{http.MethodPatch, func(p PathItem) *Operation { return p.Patch }},
+		{http.MethodDelete, func(p PathItem) *Operation { return p.Delete }},
+	}
+	seen := map[string]bool{}
+	for _, pe := range doc.Paths {
+		for _, m := range methods {
+			op := m.op(pe.Item)
+			if op == nil {
+				continue
+			}
+			ep, err := b.endpoint(pe.Path, m.name, pe.Item.Parameters, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", m.name, pe.Path, err)
+			}
+			if seen[ep.Name] {
+				return nil, fmt.Errorf("%s %s: duplicate operation name %q", m.name, pe.Path, ep.Name)
+			}
+			seen[ep.Name] = true
+			b.api.Endpoints = append(b.api.Endpoints, ep)
+		}
+	}
+	b.finish()
+	sort.SliceStable(b.api.Models, func(i, j int) bool { return b.api.Models[i].Ordinal < b.api.Models[j].Ordinal })
+	return b.api, nil
+}
+
+type builder struct {
+	doc    *Document
+	api    *API
+	byName map[string]*Model
+	next   int
+}
+
+var typeIdent = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+func (b *builder) finish() {
+	imports := map[string]bool{}
+	for _, ep := range b.api.Endpoints {
+		var vars []string
+		for _, p := range ep.PathParams {
+			vars = append(vars, p.GoVar)
+		}
+		for _, p := range ep.QueryParams {
+			vars = append(vars, p.GoVar)
+		}
+		if ep.Body != "" {
+			b.api.HasBodies = true
+			vars = append(vars, "req")
+			imports[ep.Body] = true
+		}
+		for _, id := range typeIdent.FindAllString(ep.TSResponse, -1) {
+			if _, ok := b.byName[id]; ok {
+				imports[id] = true
+			}
+		}
+		if len(vars) > 0 {
+			ep.GoUnused = strings.TrimSuffix(strings.Repeat("_, ", len(vars)), ", ") + " = " + strings.Join(vars, ", ")
+		}
+	}
+	for name := range imports {
+		b.api.TSImports = append(b.api.TSImports, name)
+	}
+	sort.Strings(b.api.TSImports)
+}
+
+func (b *builder) endpoint(path, method string, shared []*Parameter, op *Operation) (*Endpoint, error) {
+	name := op.OperationID
+	if name == "" {
+		name = strings.ToLower(method) + " " + pathParam.ReplaceAllString(path, "by $1")
+	}
+	ep := &Endpoint{
+		Name:          naming.Pascal(name),
+		HandlerName:   naming.Camel(name),
+		Summary:       strings.TrimSpace(op.Summary),
+		Method:        method,
+		Path:          path,
+		SuccessStatus: http.StatusOK,
+	}
+	ep.RoutePath = pathParam.ReplaceAllStringFunc(path, func(m string) string {
+		return ":" + naming.Camel(m[1:len(m)-1])
+	})
+
+	params, err := b.doc.parameters(shared, op.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	var rules []paramRule
+	for _, p := range params {
+		param := &Param{
+			Name:     p.Name,
+			Var:      naming.Camel(p.Name),
+			GoVar:    naming.GoCamel(p.Name),
+			Required: p.Required || p.In == "path",
+		}
+		if p.Schema != nil {
+			s := p.Schema
+			if s.Ref != "" {
+				if _, s, err = b.doc.schemaRef(s.Ref); err != nil {
+					return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+				}
+			}
+			if t, ok := paramTypes[s.Type.Name]; ok {
+				param.Type, param.GoParse, param.Kind = s.Type.Name, t.parse, t.kind
+			}
+		}
+		switch p.In {
+		case "path":
+			ep.PathParams = append(ep.PathParams, param)
+			rules = append(rules, paramRule{In: "path", Name: param.Var, Type: param.Type})
+		case "query":
+			ep.QueryParams = append(ep.QueryParams, param)
+			rules = append(rules, paramRule{In: "query", Name: param.Name, Type: param.Type, Required: param.Required})
+		default:
+			continue
+		}
+		if param.Type != "" {
+			b.api.TypedParams = true
+		}
+	}
+	// Path parameters are always present, so untyped ones need no check.
+	for _, r := range rules {
+		if r.Type != "" || r.Required {
+			out, err := json.Marshal(rules)
+			if err != nil {
+				return nil, err
+			}
+			ep.ParamRules = string(out)
+			break
+		}
+	}
+
+	body, err := b.doc.requestBody(op.RequestBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		if s := jsonSchema(body.Content); s != nil {
+			m, err := b.named(ep.Name+"Request", s)
+			if err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+			ep.Body = m
+			ep.BodyRequired = body.Required
+		}
+	}
+
+	for _, re := range op.Responses {
+		code, err := strconv.Atoi(re.Status)
+		if err != nil || code < 200 || code > 299 {
+			continue
+		}
+		ep.SuccessStatus = code
+		resp, err := b.doc.response(re.Response)
+		if err != nil {
+			return nil, err
+		}
+		if s := jsonSchema(resp.Content); s != nil {
+			f, err := b.field("", s, true, ep.Name+"Response")
+			if err != nil {
+				return nil, fmt.Errorf("response %s: %w", re.Status, err)
+			}
+			ep.GoResponse, ep.TSResponse = f.GoType, f.TSType
+		}
+		break
+	}
+	return ep, nil
+}
+
+// named returns the model name for s, registering an inline object schema
+// under hint. Body validation needs a named model, so primitive bodies are
+// registered as aliases.
+func (b *builder) named(hint string, s *Schema) (string, error) {
+	if s.Ref != "" {
+		name, _, err := b.doc.schemaRef(s.Ref)
+		return naming.Pascal(name), err
+	}
+	m, err := b.model(hint, s)
+	if err != nil {
+		return "", err
+	}
+	return m.Name, nil
+}
+
+func (b *builder) model(name string, s *Schema) (*Model, error) {
+	if m, ok := b.byName[name]; ok {
+		return m, nil
+	}
+	m := &Model{Name: name, Description: strings.TrimSpace(s.Description), Ordinal: b.next}
+	b.next++
+	b.byName[name] = m
+	b.api.Models = append(b.api.Models, m)
+
+	props, required, err := b.flatten(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(props) == 0 && s.Type.Name != "object" {
+		alias, err := b.field("", s, true, name+"Value")
+		if err != nil {
+			return nil, err
+		}
+		m.Alias = alias
+	}
+	for _, p := range props {
+		f, err := b.field(p.Name, p.Schema, contains(required, p.Name), name+naming.Pascal(p.Name))
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", p.Name, err)
+		}
+		m.Fields = append(m.Fields, f)
+	}
+	r, err := b.rule(s)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	m.Rules = string(out)
+	return m, nil
+}
+
+// flatten merges allOf members into a single property list.
+func (b *builder) flatten(s *Schema) (Schemas, []string, error) {
+	if s.Ref != "" {
+		_, target, err := b.doc.schemaRef(s.Ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		return b.flatten(target)
+	}
+	props := append(Schemas{}, s.Properties...)
+	required := append([]string{}, s.Required...)
+	for _, part := range s.AllOf {
+		p, r, err := b.flatten(part)
+		if err != nil {
+			return nil, nil, err
+		}
+		props = append(props, p...)
+		required = append(required, r...)
+	}
+	return props, required, nil
+}
+
+func (b *builder) field(name string, s *Schema, required bool, hint string) (*Field, error) {
+	f := &Field{
+		JSONName: name,
+		GoName:   naming.Pascal(name),
+		TSName:   name,
+		Required: required,
+	}
+	if !jsIdent.MatchString(name) {
+		f.TSName = strconv.Quote(name)
+	}
+	goType, tsType, err := b.types(s, hint)
+	if err != nil {
+		return nil, err
+	}
+	f.GoType, f.TSType = goType, tsType
+
+	resolved := s
+	if s.Ref != "" {
+		if _, target, err := b.doc.schemaRef(s.Ref); err == nil {
+			resolved = target
+		}
+	}
+	scalar := !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") && goType != "any"
+	nullable := s.Nullable || s.Type.Nullable
+	// binding:"required" rejects 0, so required numbers are pointers too and
+	// the rule only checks that they were sent.
+	number := resolved.Type.Name == "integer" || resolved.Type.Name == "number"
+	if scalar && (!required || nullable || number) && name != "" {
+		f.GoType = "*" + goType
+	}
+	if nullable {
+		f.TSType += " | null"
+	}
+
+	if name != "" {
+		jsonTag := name
+		if !required {
+			jsonTag += ",omitempty"
+		}
+		f.GoTag = fmt.Sprintf("`json:%q", jsonTag)
+		if binding := bindingTag(resolved, goType, required); binding != "" {
+			f.GoTag += fmt.Sprintf(" binding:%q", binding)
+		}
+		f.GoTag += "`"
+	}
+	return f, nil
+}
+
+func (b *builder) types(s *Schema, hint string) (string, string, error) {
+	if s.Ref != "" {
+		name, _, err := b.doc.schemaRef(s.Ref)
+		if err != nil {
+			return "", "", err
+		}
+		return naming.Pascal(name), naming.Pascal(name), nil
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return b.types(s.AllOf[0], hint)
+	}
+	switch s.Type.Name {
+	case "string":
+		if len(s.Enum) > 0 {
+			quoted := make([]string, len(s.Enum))
+			for i, v := range s.Enum {
+				quoted[i] = strconv.Quote(v)
+			}
+			return "string", strings.Join(quoted, " | "), nil
+		}
+		if s.Format == "date-time" {
+			b.api.UsesTime = true
+			return "time.Time", "string", nil
+		}
+		return "string", "string", nil
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", "number", nil
+		}
+		return "int64", "number", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", "number", nil
+		}
+		return "float64", "number", nil
+	case "boolean":
+		return "bool", "boolean", nil
+	case "array":
+		if s.Items == nil {
+			return "[]any", "unknown[]", nil
+		}
+		goType, tsType, err := b.types(s.Items, hint+"Item")
+		if err != nil {
+			return "", "", err
+		}
+		if strings.Contains(tsType, " ") {
+			tsType = "(" + tsType + ")"
+		}
+		return "[]" + goType, tsType + "[]", nil
+	case "object", "":
+		if len(s.Properties) == 0 && len(s.AllOf) == 0 {
+			if s.Type.Name == "" {
+				return "any", "unknown", nil
+			}
+			return "map[string]any", "Record<string, unknown>", nil
+		}
+		m, err := b.model(hint, s)
+		if err != nil {
+			return "", "", err
+		}
+		return m.Name, m.Name, nil
+	default:
+		return "", "", fmt.Errorf("unsupported schema type %q", s.Type.Name)
+	}
+}
+
+func bindingTag(s *Schema, goType string, required bool) string {
+	var rules []string
+	switch {
+	case required && goType != "bool":
+		rules = append(rules, "required")
+	case !required:
+		rules = append(rules, "omitempty")
+	}
+	if len(s.Enum) > 0 && !containsSpace(s.Enum) {
+		rules = append(rules, "oneof="+strings.Join(s.Enum, " "))
+	}
+	switch s.Type.Name {
+	case "string":
+		if s.MinLength != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinLength))
+		}
+		if s.MaxLength != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxLength))
+		}
+		switch s.Format {
+		case "email", "uuid", "uri":
+			rules = append(rules, map[string]string{"email": "email", "uuid": "uuid", "uri": "uri"}[s.Format])
+		}
+	case "integer", "number":
+		if s.Minimum != nil {
+			rules = append(rules, "gte="+strconv.FormatFloat(*s.Minimum, 'f', -1, 64))
+		}
+		if s.Maximum != nil {
+			rules = append(rules, "lte="+strconv.FormatFloat(*s.Maximum, 'f', -1, 64))
+		}
+	case "array":
+		if s.Items != nil && (s.Items.Ref != "" || len(s.Items.Properties) > 0) {
+			rules = append(rules, "dive")
+		}
+	}
+	if len(rules) == 1 && rules[0] == "omitempty" {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// rule is the JSON shape understood by the generated Node validator.
+type rule struct {
+	Ref        string     `json:"ref,omitempty"`
+	Type       string     `json:"type,omitempty"`
+	Format     string     `json:"format,omitempty"`
+	Nullable   bool       `json:"nullable,omitempty"`
+	Required   []string   `json:"required,omitempty"`
+	Properties namedRules `json:"properties,omitempty"`
+	Items      *rule      `json:"items,omitempty"`
+	Enum       []string   `json:"enum,omitempty"`
+	MinLength  *int       `json:"minLength,omitempty"`
+	MaxLength  *int       `json:"maxLength,omitempty"`
+	Minimum    *float64   `json:"minimum,omitempty"`
+	Maximum    *float64   `json:"maximum,omitempty"`
+	Pattern    string     `json:"pattern,omitempty"`
+}
+
+type namedRule struct {
+	Name string
+	Rule *rule
+}
+
+type namedRules []namedRule
+
+func (n namedRules) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, nr := range n {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(nr.Name)
+		v, err := json.Marshal(nr.Rule)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (b *builder) rule(s *Schema) (*rule, error) {
+	if s.Ref != "" {
+		name, _, err := b.doc.schemaRef(s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return &rule{Ref: naming.Pascal(name), Nullable: s.Nullable}, nil
+	}
+	r := &rule{
+		Type:      s.Type.Name,
+		Format:    s.Format,
+		Nullable:  s.Nullable || s.Type.Nullable,
+		Enum:      s.Enum,
+		MinLength: s.MinLength,
+		MaxLength: s.MaxLength,
+		Minimum:   s.Minimum,
+		Maximum:   s.Maximum,
+		Pattern:   s.Pattern,
+	}
+	props, required, err := b.flatten(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(props) > 0 {
+		r.Type = "object"
+		r.Required = required
+		for _, p := range props {
+			pr, err := b.rule(p.Schema)
+			if err != nil {
+				return nil, err
+			}
+			r.Properties = append(r.Properties, namedRule{Name: p.Name, Rule: pr})
+		}
+	}
+	if s.Items != nil {
+		ir, err := b.rule(s.Items)
+		if err != nil {
+			return nil, err
+		}
+		r.Items = ir
+	}
+	return r, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSpace(list []string) bool {
+	for _, v := range list {
+		if strings.ContainsAny(v, " \t") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Generate writes models, handler stubs, validation and routes for doc into
+// the project at targetDir and registers the routes with the server. Existing
+// generated files are only replaced when force is set.
+func Generate(targetDir string, meta project.Meta, doc *Document, force bool) error {
+	projectName := ""
+	if meta.Stack == "go-gin" {
+		mod, err := project.GoModule(targetDir)
+		if err != nil {
+			return fmt.Errorf("openapi: %w", err)
+		}
+		projectName = mod
+	}
+	api, err := Build(doc, projectName)
+	if err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+
+	var routesFile, serverFile string
+	switch meta.Stack {
+	case "go-gin":
+		routesFile = filepath.Join("internal", "routes", "api.go")
+		serverFile = filepath.Join("cmd", "main.go")
+	case "node-express":
+		routesFile = filepath.Join("src", "routes", "api.js")
+		serverFile = filepath.Join("src", "server.js")
+	case "node-express-ts":
+		routesFile = filepath.Join("src", "routes", "api.ts")
+		serverFile = filepath.Join("src", "server.ts")
+	default:
+		return fmt.Errorf("openapi: unsupported stack %q", meta.Stack)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, routesFile)); err == nil && !force {
+		return fmt.Errorf("openapi: %s already exists (use --force to regenerate)", routesFile)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("openapi: %w", err)
+	}
+
+	if err := project.WriteTemplates(templatesFS, "templates/"+meta.Stack, targetDir, api); err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	if err := project.WriteErrorHelper(targetDir, meta.Stack, projectName, meta.HasPlugin("problems")); err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	if meta.Stack == "go-gin" {
+		if err := project.FormatGo(targetDir,
+			filepath.Join("internal", "models", "api.go"),
+			filepath.Join("internal", "handlers", "api.go"),
+			routesFile,
+		); err != nil {
+			return fmt.Errorf("openapi: %w", err)
+		}
+	}
+
+	server := filepath.Join(targetDir, serverFile)
+	registered, err := project.FileContains(server, "RegisterAPI(")
+	if err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	if !registered {
+		registered, err = project.FileContains(server, "apiRouter")
+		if err != nil {
+			return fmt.Errorf("openapi: %w", err)
+		}
+	}
+	if registered {
+		return nil
+	}
+	if meta.Stack == "go-gin" {
+		injection := "apiHandler := handlers.NewAPIHandler()\nroutes.RegisterAPI(router, apiHandler)\n"
+		if err := project.InjectAtMarker(server, "// scaffold:routes", injection); err != nil {
+			return fmt.Errorf("openapi: %w", err)
+		}
+		return nil
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import apiRouter from "./routes/api.js";`); err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:routes", `app.use("/", apiRouter);`); err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the subset of an OpenAPI 3.x document the generator needs.
+// YAML and JSON documents are both accepted.
+type Document struct {
+	OpenAPI    string     `yaml:"openapi"`
+	Info       Info       `yaml:"info"`
+	Paths      Paths      `yaml:"paths"`
+	Components Components `yaml:"components"`
+}
+
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type Components struct {
+	Schemas       Schemas                 `yaml:"schemas"`
+	Parameters    map[string]*Parameter   `yaml:"parameters"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	Responses     map[string]*Response    `yaml:"responses"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Patch      *Operation   `yaml:"patch"`
+}
+
+type Operation struct {
+	OperationID string       `yaml:"operationId"`
+	Summary     string       `yaml:"summary"`
+	Tags        []string     `yaml:"tags"`
+	Parameters  []*Parameter `yaml:"parameters"`
+	RequestBody *RequestBody `yaml:"requestBody"`
+	Responses   Responses    `yaml:"responses"`
+}
+
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Ref      string               `yaml:"$ref"`
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Ref         string               `yaml:"$ref"`
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Schema struct {
+	Ref                  string    `yaml:"$ref"`
+	Type                 TypeName  `yaml:"type"`
+	Format               string    `yaml:"format"`
+	Description          string    `yaml:"description"`
+	Properties           Schemas   `yaml:"properties"`
+	Required             []string  `yaml:"required"`
+	Items                *Schema   `yaml:"items"`
+	Enum                 []string  `yaml:"enum"`
+	Nullable             bool      `yaml:"nullable"`
+	MinLength            *int      `yaml:"minLength"`
+	MaxLength            *int      `yaml:"maxLength"`
+	Minimum              *float64  `yaml:"minimum"`
+	Maximum              *float64  `yaml:"maximum"`
+	Pattern              string    `yaml:"pattern"`
+	AllOf                []*Schema `yaml:"allOf"`
+	AdditionalProperties any       `yaml:"additionalProperties"`
+}
+
+// TypeName accepts both the 3.0 form (type: string) and the 3.1 form
+// (type: [string, "null"]); "null" only marks the schema as nullable.
+type TypeName struct {
+	Name     string
+	Nullable bool
+}
+
+func (t *TypeName) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		t.Name = n.Value
+		return nil
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			if item.Value == "null" {
+				t.Nullable = true
+			} else if t.Name == "" {
+				t.Name = item.Value
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("line %d: invalid schema type", n.Line)
+	}
+}
+
+// NamedSchema is one entry of an ordered schema map.
+type NamedSchema struct {
+	Name   string
+	Schema *Schema
+}
+
+// Schemas keeps the order in which the document declares schemas and
+// properties so the generated code reads like the spec.
+type Schemas []NamedSchema
+
+func (s *Schemas) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var sch Schema
+		if err := n.Content[i+1].Decode(&sch); err != nil {
+			return err
+		}
+		*s = append(*s, NamedSchema{Name: n.Content[i].Value, Schema: &sch})
+	}
+	return nil
+}
+
+func (s Schemas) Get(name string) *Schema {
+	for _, ns := range s {
+		if ns.Name == name {
+			return ns.Schema
+		}
+	}
+	return nil
+}
+
+type PathEntry struct {
+	Path string
+	Item PathItem
+}
+
+type Paths []PathEntry
+
+func (p *Paths) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: paths must be a mapping", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var item PathItem
+		if err := n.Content[i+1].Decode(&item); err != nil {
+			return err
+		}
+		*p = append(*p, PathEntry{Path: n.Content[i].Value, Item: item})
+	}
+	return nil
+}
+
+type ResponseEntry struct {
+	Status   string
+	Response *Response
+}
+
+type Responses []ResponseEntry
+
+func (r *Responses) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: responses must be a mapping", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var resp Response
+		if err := n.Content[i+1].Decode(&resp); err != nil {
+			return err
+		}
+		*r = append(*r, ResponseEntry{Status: n.Content[i].Value, Response: &resp})
+	}
+	return nil
+}
+
+// Load reads and parses an OpenAPI document from disk. Only local
+// references ("#/components/...") are supported; nothing is fetched.
+func Load(path string) (*Document, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	return Parse(b)
+}
+
+func Parse(b []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: parse document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q (expected 3.x)", doc.OpenAPI)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, fmt.Errorf("openapi: document has no paths")
+	}
+	return &doc, nil
+}
+
+func (d *Document) schemaRef(ref string) (string, *Schema, error) {
+	const prefix = "#/components/schemas/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", nil, fmt.Errorf("unsupported $ref %q (only %s... is supported)", ref, prefix)
+	}
+	name := strings.TrimPrefix(ref, prefix)
+	s := d.Components.Schemas.Get(name)
+	if s == nil {
+		return "", nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return name, s, nil
+}
+
+func (d *Document) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+	if r, ok := d.Components.Parameters[name]; ok && r.Ref == "" {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unresolved $ref %q", p.Ref)
+}
+
+// parameters resolves the parameters of a path item and of one of its
+// operations. An operation parameter overrides the path-level one with the
+// same name and location, in its place.
+func (d *Document) parameters(shared, own []*Parameter) ([]*Parameter, error) {
+	var out []*Parameter
+	index := make(map[string]int)
+	for _, raw := range append(append([]*Parameter{}, shared...), own...) {
+		p, err := d.parameter(raw)
+		if err != nil {
+			return nil, err
+		}
+		key := p.In + " " + p.Name
+		if i, ok := index[key]; ok {
+			out[i] = p
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (d *Document) requestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	name := strings.TrimPrefix(b.Ref, "#/components/requestBodies/")
+	if r, ok := d.Components.RequestBodies[name]; ok && r.Ref == "" {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unresolved $ref %q", b.Ref)
+}
+
+func (d *Document) response(r *Response) (*Response, error) {
+	if r.Ref == "" {
+		return r, nil
+	}
+	name := strings.TrimPrefix(r.Ref, "#/components/responses/")
+	if resp, ok := d.Components.Responses[name]; ok && resp.Ref == "" {
+		return resp, nil
+	}
+	return nil, fmt.Errorf("unresolved $ref %q", r.Ref)
+}
+
+func jsonSchema(content map[string]MediaType) *Schema {
+	for ct, mt := range content {
+		if strings.Contains(ct, "json") && mt.Schema != nil {
+			return mt.Schema
+		}
+	}
+	return nil
+}
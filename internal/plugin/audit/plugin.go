@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type auditPlugin struct{}
+
+func init() {
+	plugin.Register(&auditPlugin{})
+}
+
+func (*auditPlugin) Name() string {
+	return "audit"
+}
+
+func (*auditPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *auditPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("audit plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = project.WriteMigration(ctx.TargetDir, ctx.Database, "create_audit_log", templatesFS)
+	}
+	if err != nil {
+		return fmt.Errorf("audit plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *auditPlugin) applyGoGin(ctx *plugin.Context) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	// The actor comes from the auth plugin's bearer token; without it every
+	// entry is anonymous and there is nothing to configure.
+	middleware := "audit.Middleware(auditStore)"
+	if ctx.Has("auth") {
+		configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+		if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "AuditActorClaim string"); err != nil {
+			return err
+		}
+		if err := project.InjectAtMarker(configGo, "// scaffold:config-values", `AuditActorClaim: getenvDefault("AUDIT_ACTOR_CLAIM", "sub"),`); err != nil {
+			return err
+		}
+		if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+			return err
+		}
+		if err := project.AppendEnvExample(ctx.TargetDir, envExample); err != nil {
+			return err
+		}
+		middleware = "audit.Middleware(auditStore, cfg.AuditActorClaim)"
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/audit")); err != nil {
+		return err
+	}
+	var setup string
+	switch ctx.Database {
+	case "postgresql":
+		setup = "auditStore := audit.NewStore(dbPool)\n"
+	case "sqlite":
+		setup = "auditStore := audit.NewStore(sqlDB)\n"
+	case "mongodb":
+		setup = `auditStore, err := audit.NewStore(ctx, mongoClient.Database(cfg.MongoDBName))
+if err != nil {
+	slog.Error("audit store setup failed", "err", err)
+	os.Exit(1)
+}
+`
+	}
+	setup += "router.Use(" + middleware + ")"
+	if err := project.InjectAtMarker(mainGo, "// scaffold:middleware", setup); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(mainGo, "// scaffold:routes", "routes.RegisterAudit(router, handlers.NewAuditHandler(auditStore))")
+}
+
+func (p *auditPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if ctx.Has("auth") {
+		block := `audit: {
+  actorClaim: process.env.AUDIT_ACTOR_CLAIM || "sub",
+},`
+		if err := project.InjectAtMarker(filepath.Join(ctx.TargetDir, "src", "config", "config."+ext), "// scaffold:config", block); err != nil {
+			return err
+		}
+		if err := project.AppendEnvExample(ctx.TargetDir, envExample); err != nil {
+			return err
+		}
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	imports := `import { auditMiddleware } from "./audit/audit.js";
+import auditRouter from "./routes/audit.js";`
+	if ctx.Database == "mongodb" {
+		imports += "\n" + `import { ensureAuditIndexes } from "./audit/store.js";`
+		if err := project.InjectAtMarker(server, "// scaffold:startup", "await ensureAuditIndexes();"); err != nil {
+			return err
+		}
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:imports", imports); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:middleware", "app.use(auditMiddleware);"); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:routes", `app.use("/audit", auditRouter);`)
+}
+
+const envExample = `AUDIT_ACTOR_CLAIM=sub
+`
@@ -0,0 +1,136 @@
+package cron
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// data is passed to the templates.
+type data struct {
+	*plugin.Context
+	DBModule string
+}
+
+var nodeDBModules = map[string]string{
+	"postgresql": "postgres",
+	"mongodb":    "mongo",
+	"sqlite":     "sqlite",
+}
+
+const defaultCleanupSchedule = "0 3 * * *"
+
+type cronPlugin struct{}
+
+func init() {
+	plugin.Register(&cronPlugin{})
+}
+
+func (*cronPlugin) Name() string {
+	return "cron"
+}
+
+func (*cronPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *cronPlugin) Apply(ctx *plugin.Context) error {
+	d := &data{Context: ctx, DBModule: nodeDBModules[ctx.Database]}
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(d)
+	case "node-express":
+		err = p.applyNode(d, "js")
+	case "node-express-ts":
+		err = p.applyNode(d, "ts")
+	default:
+		return fmt.Errorf("cron plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.AppendEnvExample(d.TargetDir, fmt.Sprintf("CRON_CLEANUP=%q\n", defaultCleanupSchedule))
+	}
+	if err != nil {
+		return fmt.Errorf("cron plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *cronPlugin) applyGoGin(d *data) error {
+	for _, dir := range []string{"common", d.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, d.TargetDir, d); err != nil {
+			return err
+		}
+	}
+	if err := project.FormatGo(d.TargetDir, "cmd/scheduler/main.go"); err != nil {
+		return err
+	}
+	if err := project.AddGoRequires(d.TargetDir, map[string]string{"github.com/robfig/cron/v3": "v3.0.1"}); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(d.TargetDir, "config", "config.go")
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "CronCleanup string"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", fmt.Sprintf("CronCleanup: getenvDefault(\"CRON_CLEANUP\", %q),", defaultCleanupSchedule)); err != nil {
+		return err
+	}
+	if err := project.FormatGo(d.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	if !d.UseDocker {
+		return nil
+	}
+	dockerfile := filepath.Join(d.TargetDir, "Dockerfile")
+	build := "RUN CGO_ENABLED=0 GOOS=linux go build -o /out/app ./cmd\n"
+	if err := project.ReplaceInFile(dockerfile, build, build+"RUN CGO_ENABLED=0 GOOS=linux go build -o /out/scheduler ./cmd/scheduler\n"); err != nil {
+		return err
+	}
+	copyApp := "COPY --from=build /out/app /app\n"
+	if err := project.ReplaceInFile(dockerfile, copyApp, copyApp+"COPY --from=build /out/scheduler /scheduler\n"); err != nil {
+		return err
+	}
+	return project.AddComposeServiceFromApp(d.TargetDir, "scheduler", `entrypoint: ["/scheduler"]`)
+}
+
+func (p *cronPlugin) applyNode(d *data, ext string) error {
+	for _, dir := range []string{"common", d.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+d.StackKey+"/"+dir, d.TargetDir, d); err != nil {
+			return err
+		}
+	}
+	if err := project.AddNPMDependencies(d.TargetDir, map[string]string{"croner": "^9.0.0"}, false); err != nil {
+		return err
+	}
+
+	scripts := map[string]string{"scheduler": "node src/scheduler.js"}
+	command := `command: ["npm", "run", "scheduler"]`
+	if ext == "ts" {
+		scripts = map[string]string{"scheduler": "node dist/scheduler.js", "dev:scheduler": "tsx src/scheduler.ts"}
+		command = `command: ["node", "dist/scheduler.js"]`
+	}
+	if err := project.AddNPMScripts(d.TargetDir, scripts); err != nil {
+		return err
+	}
+
+	configFile := filepath.Join(d.TargetDir, "src", "config", "config."+ext)
+	block := fmt.Sprintf(`cron: {
+  cleanup: process.env.CRON_CLEANUP || %q,
+},`, defaultCleanupSchedule)
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	if !d.UseDocker {
+		return nil
+	}
+	return project.AddComposeServiceFromApp(d.TargetDir, "scheduler", command)
+}
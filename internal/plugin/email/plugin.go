@@ -0,0 +1,234 @@
+package email
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+// The mail templates under templates/mail belong to the generated project
+// and are copied without rendering.
+//
+//go:embed templates
+var templatesFS embed.FS
+
+const mailpitService = `  mailpit:
+    image: axllent/mailpit:v1.20
+    ports:
+      - "1025:1025"
+      - "8025:8025"
+`
+
+type emailPlugin struct{}
+
+func init() {
+	plugin.Register(&emailPlugin{})
+}
+
+func (*emailPlugin) Name() string {
+	return "email"
+}
+
+func (*emailPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *emailPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("email plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = p.applyEnv(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("email plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *emailPlugin) applyGoGin(ctx *plugin.Context) error {
+	dirs := []string{"go-gin"}
+	if ctx.Has("auth") {
+		dirs = append(dirs, "go-gin-auth")
+	}
+	for _, dir := range dirs {
+		if err := project.WriteTemplates(templatesFS, "templates/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+		if err := project.CopyFiles(templatesFS, "templates/mail/"+dir, ctx.TargetDir); err != nil {
+			return err
+		}
+	}
+	if err := project.FormatGo(ctx.TargetDir, "internal/mail/templates.go"); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	fields := `MailDriver string
+MailFrom string
+SMTPHost string
+SMTPPort string
+SMTPUsername string
+SMTPPassword string`
+	values := fmt.Sprintf(`MailDriver: getenvDefault("MAIL_DRIVER", "log"),
+MailFrom: getenvDefault("MAIL_FROM", %q),
+SMTPHost: getenvDefault("SMTP_HOST", "localhost"),
+SMTPPort: getenvDefault("SMTP_PORT", "1025"),
+SMTPUsername: os.Getenv("SMTP_USERNAME"),
+SMTPPassword: os.Getenv("SMTP_PASSWORD"),`, mailFrom(ctx))
+	if ctx.Has("auth") {
+		fields += `
+MailLinkBaseURL string
+PasswordResetTTL time.Duration
+EmailVerifyTTL time.Duration`
+		load := `passwordResetTTL, err := parseDuration(getenvDefault("PASSWORD_RESET_TTL", "1h"))
+if err != nil {
+	return Config{}, fmt.Errorf("PASSWORD_RESET_TTL: %w", err)
+}
+emailVerifyTTL, err := parseDuration(getenvDefault("EMAIL_VERIFY_TTL", "24h"))
+if err != nil {
+	return Config{}, fmt.Errorf("EMAIL_VERIFY_TTL: %w", err)
+}
+`
+		if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+			return err
+		}
+		values += `
+MailLinkBaseURL: getenvDefault("MAIL_LINK_BASE_URL", "http://localhost:3000"),
+PasswordResetTTL: passwordResetTTL,
+EmailVerifyTTL: emailVerifyTTL,`
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", fields); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", values); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/mail")); err != nil {
+		return err
+	}
+	startup := `mailer, err := mail.New(cfg)
+if err != nil {
+	slog.Error("mailer setup failed", "err", err)
+	os.Exit(1)
+}
+`
+	if !ctx.Has("auth") {
+		startup += "_ = mailer // pass to the services that send mail, e.g. mail.Welcome\n"
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:startup", startup); err != nil {
+		return err
+	}
+	if !ctx.Has("auth") {
+		return nil
+	}
+	accounts := `accountSvc := services.NewAccountService(services.StubAccounts{}, mailer, os.Getenv("JWT_SECRET"), cfg)
+routes.RegisterAccount(router, handlers.NewAccountHandler(accountSvc))`
+	return project.InjectAtMarker(mainGo, "// scaffold:auth", accounts)
+}
+
+func (p *emailPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	dirs := []string{ctx.StackKey}
+	if ctx.Has("auth") {
+		dirs = append(dirs, ctx.StackKey+"-auth")
+	}
+	for _, dir := range dirs {
+		if err := project.WriteTemplates(templatesFS, "templates/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"nodemailer": "^6.9.16"}, false); err != nil {
+		return err
+	}
+	if ext == "ts" {
+		if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"@types/nodemailer": "^6.4.16"}, true); err != nil {
+			return err
+		}
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := fmt.Sprintf(`mail: {
+  driver: process.env.MAIL_DRIVER || "log",
+  from: process.env.MAIL_FROM || %q,
+  smtp: {
+    host: process.env.SMTP_HOST || "localhost",
+    port: parseInt(process.env.SMTP_PORT || "1025", 10),
+    username: process.env.SMTP_USERNAME || "",
+    password: process.env.SMTP_PASSWORD || "",
+  },`, mailFrom(ctx))
+	if ctx.Has("auth") {
+		block += `
+  linkBaseUrl: process.env.MAIL_LINK_BASE_URL || "http://localhost:3000",
+  passwordResetTtl: parseInt(process.env.PASSWORD_RESET_TTL || "3600000", 10),
+  emailVerifyTtl: parseInt(process.env.EMAIL_VERIFY_TTL || "86400000", 10),`
+	}
+	block += "\n},"
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+	if !ctx.Has("auth") {
+		return nil
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:auth-import", `import accountRouter from "./routes/account.js";`); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:auth-routes", `app.use("/auth", accountRouter);`)
+}
+
+func (p *emailPlugin) applyEnv(ctx *plugin.Context) error {
+	env := fmt.Sprintf(`MAIL_DRIVER=log
+MAIL_FROM=%q
+SMTP_HOST=localhost
+SMTP_PORT=1025
+SMTP_USERNAME=
+SMTP_PASSWORD=
+`, mailFrom(ctx))
+	if ctx.Has("auth") {
+		resetTTL, verifyTTL := "1h", "24h"
+		if ctx.StackKey != "go-gin" {
+			resetTTL, verifyTTL = "3600000", "86400000"
+		}
+		env += "MAIL_LINK_BASE_URL=http://localhost:3000\nPASSWORD_RESET_TTL=" + resetTTL + "\nEMAIL_VERIFY_TTL=" + verifyTTL + "\n"
+	}
+	if err := project.AppendEnvExample(ctx.TargetDir, env); err != nil {
+		return err
+	}
+	if !ctx.UseDocker {
+		return nil
+	}
+	if err := project.AddComposeService(ctx.TargetDir, "mailpit", mailpitService); err != nil {
+		return err
+	}
+	// Mailpit catches everything the app sends; read it on :8025.
+	for _, kv := range [][2]string{{"MAIL_DRIVER", "smtp"}, {"SMTP_HOST", "mailpit"}, {"SMTP_PORT", "1025"}} {
+		if err := project.AddComposeAppEnv(ctx.TargetDir, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return project.AddComposeAppDependsOn(ctx.TargetDir, "mailpit", false)
+}
+
+func mailFrom(ctx *plugin.Context) string {
+	return ctx.ProjectName + " <no-reply@example.com>"
+}
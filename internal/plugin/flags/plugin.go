@@ -0,0 +1,194 @@
+package flags
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type flagsPlugin struct{}
+
+func init() {
+	plugin.Register(&flagsPlugin{})
+}
+
+func (*flagsPlugin) Name() string {
+	return "flags"
+}
+
+func (*flagsPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *flagsPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("flags plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = project.WriteTemplates(templatesFS, "templates/common", ctx.TargetDir, ctx)
+	}
+	if err == nil {
+		err = project.WriteMigration(ctx.TargetDir, ctx.Database, "create_feature_flags", templatesFS)
+	}
+	if err != nil {
+		return fmt.Errorf("flags plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *flagsPlugin) applyGoGin(ctx *plugin.Context) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, map[string]string{"gopkg.in/yaml.v3": "v3.0.1"}); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	fields := `FlagsProvider string
+FlagsFile string
+FlagsRefreshInterval time.Duration`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", fields); err != nil {
+		return err
+	}
+	load := `flagsProvider := getenvDefault("FLAGS_PROVIDER", "file")
+if flagsProvider != "file" && flagsProvider != "database" {
+	return Config{}, fmt.Errorf("invalid FLAGS_PROVIDER %q (use: file|database)", flagsProvider)
+}
+flagsRefresh, err := parseDuration(getenvDefault("FLAGS_REFRESH_INTERVAL", "30s"))
+if err != nil {
+	return Config{}, fmt.Errorf("FLAGS_REFRESH_INTERVAL: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	values := `FlagsProvider: flagsProvider,
+FlagsFile: getenvDefault("FLAGS_FILE", "flags.yaml"),
+FlagsRefreshInterval: flagsRefresh,`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", values); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/flags")); err != nil {
+		return err
+	}
+	var db string
+	switch ctx.Database {
+	case "postgresql":
+		db = "dbPool"
+	case "sqlite":
+		db = "sqlDB"
+	case "mongodb":
+		db = "mongoClient.Database(cfg.MongoDBName)"
+	}
+	setup := `var flagProvider flags.Provider = flags.NewFileProvider(cfg.FlagsFile)
+if cfg.FlagsProvider == "database" {
+	flagProvider = flags.NewDatabaseProvider(` + db + `, cfg.FlagsRefreshInterval)
+}
+flagClient, err := flags.NewClient(ctx, flagProvider)
+if err != nil {
+	slog.Error("feature flags load failed", "err", err)
+	os.Exit(1)
+}
+flagClient.Start(ctx)
+router.Use(flags.Middleware(flagClient))`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:middleware", setup); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", "routes.RegisterFlags(router, handlers.NewFlagHandler(flagClient))"); err != nil {
+		return err
+	}
+	if err := project.AppendEnvExample(ctx.TargetDir, envExample("30s")); err != nil {
+		return err
+	}
+
+	// The image only holds the binary. The file is owned by the app's user
+	// so that toggles can rewrite it.
+	if !ctx.UseDocker {
+		return nil
+	}
+	copyApp := "COPY --from=build /out/app /app\n"
+	return project.ReplaceInFile(filepath.Join(ctx.TargetDir, "Dockerfile"), copyApp, copyApp+"COPY --from=build --chown=nonroot:nonroot /src/flags.yaml /flags.yaml\n")
+}
+
+func (p *flagsPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"yaml": "^2.6.0"}, false); err != nil {
+		return err
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `flags: {
+  provider: process.env.FLAGS_PROVIDER || "file",
+  file: process.env.FLAGS_FILE || "flags.yaml",
+  refreshInterval: parseInt(process.env.FLAGS_REFRESH_INTERVAL || "30000", 10),
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	imports := `import { loadFlags, stopFlags } from "./flags/flags.js";
+import { flagsMiddleware } from "./middleware/flags.js";
+import flagsRouter from "./routes/flags.js";`
+	if err := project.InjectAtMarker(server, "// scaffold:imports", imports); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:middleware", "app.use(flagsMiddleware);"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:routes", `app.use("/admin/flags", flagsRouter);`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:startup", "await loadFlags();"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:shutdown", "stopFlags();"); err != nil {
+		return err
+	}
+	if err := project.AppendEnvExample(ctx.TargetDir, envExample("30000")); err != nil {
+		return err
+	}
+
+	// The TypeScript image only keeps dist/, so the file is copied next to it.
+	if ext == "ts" && ctx.UseDocker {
+		copyDist := "COPY --from=build /app/dist ./dist\n"
+		return project.ReplaceInFile(filepath.Join(ctx.TargetDir, "Dockerfile"), copyDist, copyDist+"COPY --from=build /app/flags.yaml ./flags.yaml\n")
+	}
+	return nil
+}
+
+func envExample(refresh string) string {
+	return `FLAGS_PROVIDER=file
+FLAGS_FILE=flags.yaml
+FLAGS_REFRESH_INTERVAL=` + refresh + `
+`
+}
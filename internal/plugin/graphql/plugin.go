@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+	"project-scaffold/internal/resource"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type graphqlPlugin struct{}
+
+// The sample schema (--graphql-sample) is backed by two resources, written
+// with the resource generator so the resolvers have real services to call.
+// Without it the schema starts with a health query.
+var sampleResources = []struct {
+	name   string
+	fields []string
+}{
+	{"Post", []string{"author_id:uuid", "title:string", "body:text"}},
+	{"Author", []string{"name:string"}},
+}
+
+func init() {
+	plugin.Register(&graphqlPlugin{})
+}
+
+func (*graphqlPlugin) Name() string {
+	return "graphql"
+}
+
+func (*graphqlPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *graphqlPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("graphql plugin: unsupported stack %q", ctx.StackKey)
+	}
+	// The resources register their routes above the GraphQL endpoint, so
+	// the services it uses are declared first.
+	if err == nil && ctx.GraphQLSample {
+		err = p.generateResources(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("graphql plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *graphqlPlugin) applyGoGin(ctx *plugin.Context) error {
+	for _, dir := range []string{"common", schemaDir(ctx)} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	// graph/generated.go depends on the schema and the gqlgen version, so
+	// it is generated in the project rather than shipped.
+	if ctx.UseDocker {
+		if err := project.ReplaceInFile(filepath.Join(ctx.TargetDir, "Dockerfile"), "COPY . .\n", "COPY . .\nRUN go generate ./graph\n"); err != nil {
+			return err
+		}
+	}
+	requires := map[string]string{
+		"github.com/99designs/gqlgen":    "v0.17.55",
+		"github.com/vektah/gqlparser/v2": "v2.5.17",
+	}
+	resolver := "&graph.Resolver{HealthService: healthSvc}"
+	if ctx.GraphQLSample {
+		requires["github.com/vikstrous/dataloadgen"] = "v0.0.6"
+		resolver = "&graph.Resolver{AuthorService: authorSvc, PostService: postSvc}"
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, requires); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/graph")); err != nil {
+		return err
+	}
+	mount := `gqlDev := cfg.AppEnv == "development"
+router.POST("/graphql", graph.Handler(` + resolver + `, gqlDev))
+if gqlDev {
+	router.GET("/graphql", graph.Playground("/graphql"))
+}`
+	return project.InjectAtMarker(mainGo, "// scaffold:routes", mount)
+}
+
+func (p *graphqlPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range []string{"common", schemaDir(ctx)} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	deps := map[string]string{
+		"graphql":      "^16.9.0",
+		"graphql-yoga": "^5.10.0",
+	}
+	if ctx.GraphQLSample {
+		deps["dataloader"] = "^2.2.2"
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, deps, false); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { yoga } from "./graphql/yoga.js";`); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:routes", "app.use(yoga.graphqlEndpoint, yoga);")
+}
+
+// schemaDir holds the schema and resolvers: the Author and Post sample or
+// the health query.
+func schemaDir(ctx *plugin.Context) string {
+	if ctx.GraphQLSample {
+		return "sample"
+	}
+	return "health"
+}
+
+func (p *graphqlPlugin) generateResources(ctx *plugin.Context) error {
+	meta, err := project.ReadMeta(ctx.TargetDir)
+	if err != nil {
+		return err
+	}
+	for _, r := range sampleResources {
+		res, err := resource.New(r.name, r.fields)
+		if err != nil {
+			return err
+		}
+		if err := resource.Generate(ctx.TargetDir, meta, res, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const defaultPort = "9090"
+
+type grpcPlugin struct{}
+
+func init() {
+	plugin.Register(&grpcPlugin{})
+}
+
+func (*grpcPlugin) Name() string {
+	return "grpc"
+}
+
+func (*grpcPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *grpcPlugin) Apply(ctx *plugin.Context) error {
+	err := project.WriteTemplates(templatesFS, "templates/common", ctx.TargetDir, ctx)
+	if err == nil {
+		switch ctx.StackKey {
+		case "go-gin":
+			err = p.applyGoGin(ctx)
+		case "node-express":
+			err = p.applyNode(ctx, "js")
+		case "node-express-ts":
+			err = p.applyNode(ctx, "ts")
+		default:
+			return fmt.Errorf("grpc plugin: unsupported stack %q", ctx.StackKey)
+		}
+	}
+	if err == nil {
+		err = p.applyEnv(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("grpc plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *grpcPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, map[string]string{
+		"google.golang.org/grpc":     "v1.67.1",
+		"google.golang.org/protobuf": "v1.34.2",
+	}); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "GRPCPort string"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", fmt.Sprintf("GRPCPort: getenvDefault(\"GRPC_PORT\", %q),", defaultPort)); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/grpcserver")); err != nil {
+		return err
+	}
+	start := `grpcSrv := grpcserver.New(cfg, healthSvc)
+go func() {
+	slog.Info("grpc server starting", "addr", grpcSrv.Addr())
+	if err := grpcSrv.ListenAndServe(); err != nil {
+		slog.Error("grpc server failed", "err", err)
+		stop()
+	}
+}()`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", start); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(mainGo, "// scaffold:drain", "grpcSrv.Shutdown(shutdownCtx)")
+}
+
+func (p *grpcPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range []string{"node", ctx.StackKey} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{
+		"@grpc/grpc-js":      "^1.12.2",
+		"@grpc/proto-loader": "^0.7.13",
+		"@grpc/reflection":   "^1.0.4",
+	}, false); err != nil {
+		return err
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := fmt.Sprintf(`grpc: {
+  port: parseInt(process.env.GRPC_PORT || %q, 10),
+},`, defaultPort)
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { closeGrpcServer, startGrpcServer } from "./grpc/server.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:startup", "await startGrpcServer();"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:drain", "await closeGrpcServer();"); err != nil {
+		return err
+	}
+
+	// The protos are read at runtime, so they have to ship with the build.
+	if ext == "ts" && ctx.UseDocker {
+		copyDist := "COPY --from=build /app/dist ./dist\n"
+		return project.ReplaceInFile(filepath.Join(ctx.TargetDir, "Dockerfile"), copyDist, copyDist+"COPY --from=build /app/proto ./proto\n")
+	}
+	return nil
+}
+
+func (p *grpcPlugin) applyEnv(ctx *plugin.Context) error {
+	if err := project.AppendEnvExample(ctx.TargetDir, "GRPC_PORT="+defaultPort+"\n"); err != nil {
+		return err
+	}
+	if !ctx.UseDocker {
+		return nil
+	}
+	if err := project.ReplaceInFile(filepath.Join(ctx.TargetDir, "Dockerfile"), "EXPOSE 8080\n", "EXPOSE 8080 "+defaultPort+"\n"); err != nil {
+		return err
+	}
+	return project.AddComposeAppPort(ctx.TargetDir, "${GRPC_PORT:-"+defaultPort+"}:"+defaultPort)
+}
@@ -0,0 +1,151 @@
+package idempotency
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type idempotencyPlugin struct{}
+
+func init() {
+	plugin.Register(&idempotencyPlugin{})
+}
+
+func (*idempotencyPlugin) Name() string {
+	return "idempotency"
+}
+
+func (*idempotencyPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *idempotencyPlugin) Apply(ctx *plugin.Context) error {
+	// Keys go to Redis when the redis plugin is selected, otherwise to the
+	// project's database.
+	store := ctx.Database
+	if ctx.Has("redis") {
+		store = "redis"
+	}
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx, store)
+	case "node-express":
+		err = p.applyNode(ctx, store, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, store, "ts")
+	default:
+		return fmt.Errorf("idempotency plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil && (store == "postgresql" || store == "sqlite") {
+		err = project.WriteMigration(ctx.TargetDir, ctx.Database, "create_idempotency_keys", templatesFS)
+	}
+	if err != nil {
+		return fmt.Errorf("idempotency plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *idempotencyPlugin) applyGoGin(ctx *plugin.Context, store string) error {
+	for _, dir := range []string{"common", store} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "IdempotencyTTL time.Duration"); err != nil {
+		return err
+	}
+	load := `idempotencyTTL, err := parseDuration(getenvDefault("IDEMPOTENCY_TTL", "24h"))
+if err != nil {
+	return Config{}, fmt.Errorf("IDEMPOTENCY_TTL: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", "IdempotencyTTL: idempotencyTTL,"); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/idempotency")); err != nil {
+		return err
+	}
+	var setup string
+	switch store {
+	case "redis":
+		setup = "idemStore := idempotency.NewRedisStore(redisClient, cfg.IdempotencyTTL)\n"
+	case "postgresql":
+		setup = "idemStore := idempotency.NewPostgresStore(dbPool, cfg.IdempotencyTTL)\n"
+	case "sqlite":
+		setup = "idemStore := idempotency.NewSQLiteStore(sqlDB, cfg.IdempotencyTTL)\n"
+	case "mongodb":
+		setup = `idemStore, err := idempotency.NewMongoStore(ctx, mongoClient.Database(cfg.MongoDBName), cfg.IdempotencyTTL)
+if err != nil {
+	slog.Error("idempotency store setup failed", "err", err)
+	os.Exit(1)
+}
+`
+	}
+	setup += "idempotency.StartCleanup(ctx, idemStore)\nrouter.Use(idempotency.Middleware(idemStore))"
+	if err := project.InjectAtMarker(mainGo, "// scaffold:middleware", setup); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, "IDEMPOTENCY_TTL=24h\n")
+}
+
+func (p *idempotencyPlugin) applyNode(ctx *plugin.Context, store, ext string) error {
+	for _, dir := range []string{"common", store} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `idempotency: {
+  ttl: parseInt(process.env.IDEMPOTENCY_TTL || "86400000", 10),
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	imports := `import { idempotencyMiddleware } from "./middleware/idempotency.js";`
+	switch store {
+	case "postgresql", "sqlite":
+		imports += "\n" + `import { startIdempotencyCleanup, stopIdempotencyCleanup } from "./idempotency/store.js";`
+		if err := project.InjectAtMarker(server, "// scaffold:startup", "startIdempotencyCleanup();"); err != nil {
+			return err
+		}
+		if err := project.InjectAtMarker(server, "// scaffold:drain", "stopIdempotencyCleanup();"); err != nil {
+			return err
+		}
+	case "mongodb":
+		imports += "\n" + `import { ensureIdempotencyIndexes } from "./idempotency/store.js";`
+		if err := project.InjectAtMarker(server, "// scaffold:startup", "await ensureIdempotencyIndexes();"); err != nil {
+			return err
+		}
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:imports", imports); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:middleware", "app.use(idempotencyMiddleware);"); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, "IDEMPOTENCY_TTL=86400000\n")
+}
@@ -0,0 +1,186 @@
+package jobs
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// data is passed to the templates. Backend is "asynq" / "bullmq" when the
+// redis plugin is selected, otherwise the database key.
+type data struct {
+	*plugin.Context
+	Backend  string
+	DBModule string
+}
+
+var nodeDBModules = map[string]string{
+	"postgresql": "postgres",
+	"mongodb":    "mongo",
+	"sqlite":     "sqlite",
+}
+
+type jobsPlugin struct{}
+
+func init() {
+	plugin.Register(&jobsPlugin{})
+}
+
+func (*jobsPlugin) Name() string {
+	return "jobs"
+}
+
+func (*jobsPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+// After makes the queue see the redis plugin's config and compose entries.
+func (*jobsPlugin) After() []string {
+	return []string{"redis"}
+}
+
+func (p *jobsPlugin) Apply(ctx *plugin.Context) error {
+	d := &data{Context: ctx, Backend: ctx.Database, DBModule: nodeDBModules[ctx.Database]}
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		if ctx.Has("redis") {
+			d.Backend = "asynq"
+		}
+		err = p.applyGoGin(d)
+	case "node-express":
+		if ctx.Has("redis") {
+			d.Backend = "bullmq"
+		}
+		err = p.applyNode(d, "js")
+	case "node-express-ts":
+		if ctx.Has("redis") {
+			d.Backend = "bullmq"
+		}
+		err = p.applyNode(d, "ts")
+	default:
+		return fmt.Errorf("jobs plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteMigration(d.TargetDir, d.Backend, "create_jobs", templatesFS)
+	}
+	if err != nil {
+		return fmt.Errorf("jobs plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *jobsPlugin) applyGoGin(d *data) error {
+	dirs := []string{"common", d.Backend}
+	if d.Backend != "asynq" {
+		dirs = []string{"common", "dbqueue", d.Backend}
+	}
+	for _, dir := range dirs {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, d.TargetDir, d); err != nil {
+			return err
+		}
+	}
+	if err := project.FormatGo(d.TargetDir, "cmd/worker/main.go"); err != nil {
+		return err
+	}
+	if d.Backend == "asynq" {
+		if err := project.AddGoRequires(d.TargetDir, map[string]string{"github.com/hibiken/asynq": "v0.24.1"}); err != nil {
+			return err
+		}
+	}
+
+	configGo := filepath.Join(d.TargetDir, "config", "config.go")
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "JobsConcurrency int"); err != nil {
+		return err
+	}
+	load := `jobsConcurrency, err := strconv.Atoi(getenvDefault("JOBS_CONCURRENCY", "10"))
+if err != nil {
+	return Config{}, fmt.Errorf("JOBS_CONCURRENCY: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", "JobsConcurrency: jobsConcurrency,"); err != nil {
+		return err
+	}
+	if err := project.FormatGo(d.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+	if err := project.AppendEnvExample(d.TargetDir, "JOBS_CONCURRENCY=10\n"); err != nil {
+		return err
+	}
+
+	if !d.UseDocker {
+		return nil
+	}
+	dockerfile := filepath.Join(d.TargetDir, "Dockerfile")
+	build := "RUN CGO_ENABLED=0 GOOS=linux go build -o /out/app ./cmd\n"
+	if err := project.ReplaceInFile(dockerfile, build, build+"RUN CGO_ENABLED=0 GOOS=linux go build -o /out/worker ./cmd/worker\n"); err != nil {
+		return err
+	}
+	copyApp := "COPY --from=build /out/app /app\n"
+	if err := project.ReplaceInFile(dockerfile, copyApp, copyApp+"COPY --from=build /out/worker /worker\n"); err != nil {
+		return err
+	}
+	return project.AddComposeServiceFromApp(d.TargetDir, "worker", `entrypoint: ["/worker"]`)
+}
+
+func (p *jobsPlugin) applyNode(d *data, ext string) error {
+	dirs := []string{"common", d.Backend}
+	if d.Backend != "bullmq" {
+		dirs = []string{"common", "dbqueue", d.Backend}
+	}
+	for _, dir := range dirs {
+		if err := project.WriteTemplates(templatesFS, "templates/"+d.StackKey+"/"+dir, d.TargetDir, d); err != nil {
+			return err
+		}
+	}
+	if d.Backend == "bullmq" {
+		if err := project.AddNPMDependencies(d.TargetDir, map[string]string{"bullmq": "^5.21.2", "ioredis": "^5.4.1"}, false); err != nil {
+			return err
+		}
+	}
+
+	scripts := map[string]string{"worker": "node src/worker.js"}
+	command := `command: ["npm", "run", "worker"]`
+	if ext == "ts" {
+		scripts = map[string]string{"worker": "node dist/worker.js", "dev:worker": "tsx src/worker.ts"}
+		command = `command: ["node", "dist/worker.js"]`
+	}
+	if err := project.AddNPMScripts(d.TargetDir, scripts); err != nil {
+		return err
+	}
+
+	configFile := filepath.Join(d.TargetDir, "src", "config", "config."+ext)
+	block := `jobs: {
+  concurrency: parseInt(process.env.JOBS_CONCURRENCY || "10", 10),
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+	if d.Backend == "bullmq" {
+		// The server only opens a queue connection once it enqueues a job.
+		server := filepath.Join(d.TargetDir, "src", "server."+ext)
+		if err := project.InjectAtMarker(server, "// scaffold:imports", `import { closeQueue } from "./jobs/queue.js";`); err != nil {
+			return err
+		}
+		if err := project.InjectAtMarker(server, "// scaffold:shutdown", "await closeQueue();"); err != nil {
+			return err
+		}
+	}
+	if err := project.AppendEnvExample(d.TargetDir, "JOBS_CONCURRENCY=10\n"); err != nil {
+		return err
+	}
+
+	if !d.UseDocker {
+		return nil
+	}
+	return project.AddComposeServiceFromApp(d.TargetDir, "worker", command)
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const prometheusService = `  prometheus:
+    image: prom/prometheus:v2.54.1
+    volumes:
+      - ./prometheus.yml:/etc/prometheus/prometheus.yml:ro
+    ports:
+      - "9090:9090"
+    depends_on:
+      - app
+`
+
+type metricsPlugin struct{}
+
+func init() {
+	plugin.Register(&metricsPlugin{})
+}
+
+func (*metricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (*metricsPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *metricsPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "src/server.js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "src/server.ts")
+	default:
+		return fmt.Errorf("metrics plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err != nil {
+		return fmt.Errorf("metrics plugin: %w", err)
+	}
+	if !ctx.UseDocker {
+		return nil
+	}
+	if err := project.WriteTemplate(templatesFS, "templates/docker/prometheus.yml.tmpl", filepath.Join(ctx.TargetDir, "prometheus.yml"), ctx); err != nil {
+		return fmt.Errorf("metrics plugin: %w", err)
+	}
+	if err := project.AddComposeService(ctx.TargetDir, "prometheus", prometheusService); err != nil {
+		return fmt.Errorf("metrics plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *metricsPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin/common", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	// Pool statistics are exported where the driver exposes them.
+	dbHandle := map[string]string{"postgresql": "dbPool", "sqlite": "sqlDB"}[ctx.Database]
+	if dbHandle != "" {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+ctx.Database, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, map[string]string{
+		"github.com/prometheus/client_golang": "v1.20.5",
+	}); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/metrics")); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:middleware", "router.Use(metrics.Middleware())"); err != nil {
+		return err
+	}
+	routes := "router.GET(\"/metrics\", metrics.Handler())\n"
+	if dbHandle != "" {
+		routes += "metrics.RegisterDBStats(" + dbHandle + ")\n"
+	}
+	return project.InjectAtMarker(mainGo, "// scaffold:routes", routes)
+}
+
+func (p *metricsPlugin) applyNode(ctx *plugin.Context, serverFile string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"prom-client": "^15.1.3"}, false); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, filepath.FromSlash(serverFile))
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { metricsHandler, metricsMiddleware } from "./metrics/metrics.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:middleware", "app.use(metricsMiddleware);"); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:routes", `app.get("/metrics", metricsHandler);`)
+}
@@ -0,0 +1,227 @@
+package otel
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const collectorService = `  otel-collector:
+    image: otel/opentelemetry-collector-contrib:0.111.0
+    command: ["--config=/etc/otelcol-contrib/config.yaml"]
+    volumes:
+      - ./otel-collector.yaml:/etc/otelcol-contrib/config.yaml:ro
+    ports:
+      - "4317:4317"
+      - "4318:4318"
+    depends_on:
+      - jaeger
+`
+
+const jaegerService = `  jaeger:
+    image: jaegertracing/all-in-one:1.62.0
+    environment:
+      COLLECTOR_OTLP_ENABLED: "true"
+    ports:
+      - "16686:16686"
+`
+
+// goDBTracing holds, per database, the instrumentation import and the code
+// injected at "// scaffold:db-options" in internal/db.
+var goDBTracing = map[string]struct {
+	file, imports, options string
+	requires               map[string]string
+}{
+	"postgresql": {
+		file:     "postgres.go",
+		imports:  `"github.com/exaring/otelpgx"`,
+		options:  "poolCfg.ConnConfig.Tracer = otelpgx.NewTracer()",
+		requires: map[string]string{"github.com/exaring/otelpgx": "v0.6.2"},
+	},
+	"mongodb": {
+		file:     "mongo.go",
+		imports:  `"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"`,
+		options:  "opts.SetMonitor(otelmongo.NewMonitor())",
+		requires: map[string]string{"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo": "v0.56.0"},
+	},
+	"sqlite": {
+		file:    "sqlite.go",
+		imports: "\"github.com/XSAM/otelsql\"\n\"go.opentelemetry.io/otel/attribute\"",
+		options: `otelDriver, err := otelsql.Register(driverName, otelsql.WithAttributes(attribute.String("db.system", "sqlite")))
+if err != nil {
+	return nil, fmt.Errorf("register sqlite tracing: %w", err)
+}
+driverName = otelDriver`,
+		requires: map[string]string{"github.com/XSAM/otelsql": "v0.35.0"},
+	},
+}
+
+type otelPlugin struct{}
+
+func init() {
+	plugin.Register(&otelPlugin{})
+}
+
+func (*otelPlugin) Name() string {
+	return "otel"
+}
+
+func (*otelPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *otelPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js", "./src/tracing.js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts", "./dist/tracing.js")
+	default:
+		return fmt.Errorf("otel plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = p.applyEnv(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("otel plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *otelPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	requires := map[string]string{
+		"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin": "v0.56.0",
+		"go.opentelemetry.io/otel": "v1.31.0",
+		"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp": "v1.31.0",
+		"go.opentelemetry.io/otel/sdk":                                    "v1.31.0",
+		"go.opentelemetry.io/otel/trace":                                  "v1.31.0",
+	}
+	dbTracing, ok := goDBTracing[ctx.Database]
+	if ok {
+		for mod, version := range dbTracing.requires {
+			requires[mod] = version
+		}
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, requires); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/telemetry")); err != nil {
+		return err
+	}
+	startup := `shutdownTracing, err := telemetry.Setup(ctx)
+if err != nil {
+	slog.Error("telemetry setup failed", "err", err)
+	os.Exit(1)
+}
+defer func() { _ = shutdownTracing(context.Background()) }()
+`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:startup", startup); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:middleware", "router.Use(telemetry.Middleware()...)"); err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	dbFile := filepath.Join("internal", "db", dbTracing.file)
+	dbPath := filepath.Join(ctx.TargetDir, dbFile)
+	if err := project.InjectAtMarker(dbPath, "// scaffold:imports", dbTracing.imports); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(dbPath, "// scaffold:db-options", dbTracing.options); err != nil {
+		return err
+	}
+	return project.FormatGo(ctx.TargetDir, dbFile)
+}
+
+func (p *otelPlugin) applyNode(ctx *plugin.Context, ext, tracingEntry string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	deps := map[string]string{
+		"@opentelemetry/api":                      "^1.9.0",
+		"@opentelemetry/exporter-trace-otlp-http": "^0.54.0",
+		"@opentelemetry/instrumentation":          "^0.54.0",
+		"@opentelemetry/instrumentation-express":  "^0.44.0",
+		"@opentelemetry/instrumentation-http":     "^0.54.0",
+		"@opentelemetry/sdk-node":                 "^0.54.0",
+	}
+	// better-sqlite3 has no OpenTelemetry instrumentation.
+	switch ctx.Database {
+	case "postgresql":
+		deps["@opentelemetry/instrumentation-pg"] = "^0.47.0"
+	case "mongodb":
+		deps["@opentelemetry/instrumentation-mongodb"] = "^0.48.0"
+	}
+	if ctx.Has("redis") {
+		deps["@opentelemetry/instrumentation-ioredis"] = "^0.44.0"
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, deps, false); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { traceContextMiddleware } from "./middleware/traceContext.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:middleware", "app.use(traceContextMiddleware);"); err != nil {
+		return err
+	}
+
+	// The SDK has to be loaded before express and the driver are imported.
+	pkg := filepath.Join(ctx.TargetDir, "package.json")
+	if ext == "ts" {
+		if err := project.ReplaceInFile(pkg, `"tsx src/server.ts"`, `"tsx --import ./src/tracing.ts src/server.ts"`); err != nil {
+			return err
+		}
+		if err := project.ReplaceInFile(pkg, `"node dist/server.js"`, `"node --import `+tracingEntry+` dist/server.js"`); err != nil {
+			return err
+		}
+		if !ctx.UseDocker {
+			return nil
+		}
+		return project.ReplaceInFile(filepath.Join(ctx.TargetDir, "Dockerfile"), `CMD ["node", "dist/server.js"]`, `CMD ["node", "--import", "`+tracingEntry+`", "dist/server.js"]`)
+	}
+	if err := project.ReplaceInFile(pkg, `"node src/server.js"`, `"node --import `+tracingEntry+` src/server.js"`); err != nil {
+		return err
+	}
+	return project.ReplaceInFile(pkg, `"NODE_ENV=production node src/server.js"`, `"NODE_ENV=production node --import `+tracingEntry+` src/server.js"`)
+}
+
+func (p *otelPlugin) applyEnv(ctx *plugin.Context) error {
+	if err := project.AppendEnvExample(ctx.TargetDir, "OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318\nOTEL_SERVICE_NAME="+ctx.ProjectName+"\n"); err != nil {
+		return err
+	}
+	if !ctx.UseDocker {
+		return nil
+	}
+	if err := project.WriteTemplate(templatesFS, "templates/docker/otel-collector.yaml.tmpl", filepath.Join(ctx.TargetDir, "otel-collector.yaml"), ctx); err != nil {
+		return err
+	}
+	if err := project.AddComposeService(ctx.TargetDir, "otel-collector", collectorService); err != nil {
+		return err
+	}
+	if err := project.AddComposeService(ctx.TargetDir, "jaeger", jaegerService); err != nil {
+		return err
+	}
+	if err := project.AddComposeAppEnv(ctx.TargetDir, "OTEL_EXPORTER_OTLP_ENDPOINT", "http://otel-collector:4318"); err != nil {
+		return err
+	}
+	return project.AddComposeAppDependsOn(ctx.TargetDir, "otel-collector", false)
+}
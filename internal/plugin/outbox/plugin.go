@@ -0,0 +1,178 @@
+package outbox
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// MongoDB only runs transactions on a replica set, so the compose database
+// becomes a single-node one. The healthcheck initiates it on first start.
+const mongoReplicaSet = `    image: mongo:7
+    command: ["--replSet", "rs0", "--bind_ip_all"]
+    healthcheck:
+      test: ["CMD", "mongosh", "--quiet", "--eval", "try { rs.status() } catch (e) { rs.initiate({ _id: 'rs0', members: [{ _id: 0, host: 'db:27017' }] }) } if (!db.hello().isWritablePrimary) quit(1)"]
+      interval: 5s
+      timeout: 10s
+      retries: 20
+`
+
+type outboxPlugin struct{}
+
+func init() {
+	plugin.Register(&outboxPlugin{})
+}
+
+func (*outboxPlugin) Name() string {
+	return "outbox"
+}
+
+func (*outboxPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+// After lets the relay publish through the messaging plugin's broker.
+func (*outboxPlugin) After() []string {
+	return []string{"messaging"}
+}
+
+func (p *outboxPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("outbox plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteMigration(ctx.TargetDir, ctx.Database, "create_outbox", templatesFS)
+	}
+	if err == nil && ctx.Database == "mongodb" {
+		err = p.applyMongoReplicaSet(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("outbox plugin: %w", err)
+	}
+	return nil
+}
+
+// templateDirs lists the template directories for the stack: the relay, the
+// store for the database and the publisher.
+func templateDirs(ctx *plugin.Context) []string {
+	publisher := "log"
+	if ctx.Has("messaging") {
+		publisher = "messaging"
+	}
+	return []string{"common", ctx.Database, publisher}
+}
+
+func (p *outboxPlugin) applyGoGin(ctx *plugin.Context) error {
+	for _, dir := range templateDirs(ctx) {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/outbox")); err != nil {
+		return err
+	}
+	publisher := "outbox.LogPublisher{}"
+	if ctx.Has("messaging") {
+		publisher = "outbox.NewBrokerPublisher(msgBroker)"
+	}
+	var relay string
+	switch ctx.Database {
+	case "postgresql":
+		relay = fmt.Sprintf("outboxRelay := outbox.NewRelay(dbPool, %s)\n", publisher)
+	case "sqlite":
+		relay = fmt.Sprintf("outboxRelay := outbox.NewRelay(sqlDB, %s)\n", publisher)
+	case "mongodb":
+		relay = fmt.Sprintf(`outboxRelay, err := outbox.NewRelay(ctx, mongoClient.Database(cfg.MongoDBName), %s)
+if err != nil {
+	slog.Error("outbox relay setup failed", "err", err)
+	os.Exit(1)
+}
+`, publisher)
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", relay+"outboxRelay.Start(ctx)"); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(mainGo, "// scaffold:drain", "outboxRelay.Wait(shutdownCtx)")
+}
+
+func (p *outboxPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range templateDirs(ctx) {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if ctx.Database == "mongodb" {
+		if err := addMongoGetClient(ctx, ext); err != nil {
+			return err
+		}
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { startOutboxRelay, stopOutboxRelay } from "./outbox/outbox.js";`); err != nil {
+		return err
+	}
+	// The relay starts once the server is up, after the other plugins have
+	// connected, e.g. to the message broker.
+	if err := project.InjectAtMarker(server, "// scaffold:server", "await startOutboxRelay();"); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:drain", "await stopOutboxRelay();")
+}
+
+// addMongoGetClient exports the client from the db module, which
+// withTransaction needs to start a session.
+func addMongoGetClient(ctx *plugin.Context, ext string) error {
+	dbModule := filepath.Join(ctx.TargetDir, "src", "db", "mongo."+ext)
+	if ok, err := project.FileContains(dbModule, "export function getClient("); err != nil || ok {
+		return err
+	}
+	signature := "export function getClient() {"
+	if ext == "ts" {
+		signature = "export function getClient(): MongoClient {"
+	}
+	getClient := signature + `
+  if (!client) {
+    throw new Error("Database not connected. Call connect() first.");
+  }
+  return client;
+}
+
+`
+	getDb := "export function getDb("
+	return project.ReplaceInFile(dbModule, getDb, getClient+getDb)
+}
+
+func (p *outboxPlugin) applyMongoReplicaSet(ctx *plugin.Context) error {
+	envExample := filepath.Join(ctx.TargetDir, ".env.example")
+	hostURL := "MONGO_URL=mongodb://localhost:27017/" + ctx.ProjectName
+	note := "# The outbox uses MongoDB transactions, which need a replica set.\n"
+	if !ctx.UseDocker {
+		return project.ReplaceInFile(envExample, hostURL+"\n", note+hostURL+"\n")
+	}
+	compose := filepath.Join(ctx.TargetDir, "docker-compose.yml")
+	if err := project.ReplaceInFile(compose, "    image: mongo:7\n", mongoReplicaSet); err != nil {
+		return err
+	}
+	if err := project.AddComposeAppDependsOn(ctx.TargetDir, "db", true); err != nil {
+		return err
+	}
+	// The replica set advertises db:27017, which the host cannot resolve,
+	// so connections from outside compose skip discovery.
+	return project.ReplaceInFile(envExample, hostURL+"\n", note+hostURL+"?directConnection=true\n")
+}
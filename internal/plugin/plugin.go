@@ -6,6 +6,12 @@ type Plugin interface {
 	Apply(ctx *Context) error
 }
 
+// Dependent is implemented by plugins that build on other plugins when
+// those are selected too; they are applied after them.
+type Dependent interface {
+	After() []string
+}
+
 type Context struct {
 	ProjectName string
 	StackKey    string
@@ -13,4 +19,19 @@ type Context struct {
 	UseDocker   bool
 	TargetDir   string
 	Plugins     []string
+	// Broker is the messaging plugin's broker (nats, kafka or rabbitmq),
+	// empty unless that plugin is selected.
+	Broker string
+	// GraphQLSample is set by --graphql-sample for the graphql plugin.
+	GraphQLSample bool
+}
+
+// Has reports whether the named plugin was selected for the project.
+func (c *Context) Has(name string) bool {
+	for _, p := range c.Plugins {
+		if p == name {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,71 @@
+package problems
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type problemsPlugin struct{}
+
+func init() {
+	plugin.Register(&problemsPlugin{})
+}
+
+func (*problemsPlugin) Name() string {
+	return "problems"
+}
+
+func (*problemsPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *problemsPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("problems plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err != nil {
+		return fmt.Errorf("problems plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *problemsPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	// Recovery sits inside RequestLogger so recovered panics are logged
+	// with their 500, and outside Errors so it also covers error rendering.
+	return project.ReplaceInFile(filepath.Join(ctx.TargetDir, "cmd", "main.go"),
+		"router.Use(gin.Recovery(), middleware.RequestID(), middleware.RequestLogger())",
+		"router.Use(middleware.RequestID(), middleware.RequestLogger(), middleware.Recovery(), middleware.Errors())\n\trouter.NoRoute(middleware.NoRoute)")
+}
+
+// applyNode replaces the scaffold's errorHandler.{js,ts} with the problem
+// version and mounts its 404 handler behind every route.
+func (p *problemsPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.ReplaceInFile(server,
+		`import { errorHandlerMiddleware } from "./middleware/errorHandler.js";`,
+		`import { errorHandlerMiddleware, notFoundHandler } from "./middleware/errorHandler.js";`); err != nil {
+		return err
+	}
+	return project.ReplaceInFile(server, "app.use(errorHandlerMiddleware);", "app.use(notFoundHandler);\napp.use(errorHandlerMiddleware);")
+}
@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const envExample = `RATE_LIMIT_IP_PER_MINUTE=120
+RATE_LIMIT_API_KEY_PER_MINUTE=600
+RATE_LIMIT_API_KEY_HEADER=X-API-Key
+`
+
+type ratelimitPlugin struct{}
+
+func init() {
+	plugin.Register(&ratelimitPlugin{})
+}
+
+func (*ratelimitPlugin) Name() string {
+	return "ratelimit"
+}
+
+func (*ratelimitPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *ratelimitPlugin) Apply(ctx *plugin.Context) error {
+	// Buckets are shared through Redis when the redis plugin is selected.
+	store := "memory"
+	if ctx.Has("redis") {
+		store = "redis"
+	}
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx, store)
+	case "node-express":
+		err = p.applyNode(ctx, store, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, store, "ts")
+	default:
+		return fmt.Errorf("ratelimit plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = project.AppendEnvExample(ctx.TargetDir, envExample)
+	}
+	if err != nil {
+		return fmt.Errorf("ratelimit plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *ratelimitPlugin) applyGoGin(ctx *plugin.Context, store string) error {
+	for _, dir := range []string{"common", store} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+	if err := project.FormatGo(ctx.TargetDir, "internal/ratelimit/ratelimit.go"); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "RateLimitIPPerMinute int\nRateLimitAPIKeyPerMinute int\nRateLimitAPIKeyHeader string"); err != nil {
+		return err
+	}
+	load := `rateLimitIP, err := strconv.Atoi(getenvDefault("RATE_LIMIT_IP_PER_MINUTE", "120"))
+if err != nil {
+	return Config{}, fmt.Errorf("RATE_LIMIT_IP_PER_MINUTE: %w", err)
+}
+rateLimitAPIKey, err := strconv.Atoi(getenvDefault("RATE_LIMIT_API_KEY_PER_MINUTE", "600"))
+if err != nil {
+	return Config{}, fmt.Errorf("RATE_LIMIT_API_KEY_PER_MINUTE: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	values := `RateLimitIPPerMinute: rateLimitIP,
+RateLimitAPIKeyPerMinute: rateLimitAPIKey,
+RateLimitAPIKeyHeader: getenvDefault("RATE_LIMIT_API_KEY_HEADER", "X-API-Key"),`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", values); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/ratelimit")); err != nil {
+		return err
+	}
+	newStore := "ratelimit.NewMemoryStore()"
+	if store == "redis" {
+		newStore = "ratelimit.NewRedisStore(redisClient)"
+	}
+	return project.InjectAtMarker(mainGo, "// scaffold:middleware", "router.Use(ratelimit.Middleware("+newStore+", cfg))")
+}
+
+func (p *ratelimitPlugin) applyNode(ctx *plugin.Context, store, ext string) error {
+	for _, dir := range []string{"common", store} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `rateLimit: {
+  ipPerMinute: parseInt(process.env.RATE_LIMIT_IP_PER_MINUTE || "120", 10),
+  apiKeyPerMinute: parseInt(process.env.RATE_LIMIT_API_KEY_PER_MINUTE || "600", 10),
+  apiKeyHeader: process.env.RATE_LIMIT_API_KEY_HEADER || "X-API-Key",
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { rateLimitMiddleware } from "./middleware/rateLimit.js";`); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:middleware", "app.use(rateLimitMiddleware);")
+}
@@ -0,0 +1,157 @@
+package redis
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const redisService = `  redis:
+    image: redis:7-alpine
+    ports:
+      - "6379:6379"
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+      interval: 5s
+      timeout: 3s
+      retries: 10
+`
+
+type redisPlugin struct{}
+
+func init() {
+	plugin.Register(&redisPlugin{})
+}
+
+func (*redisPlugin) Name() string {
+	return "redis"
+}
+
+func (*redisPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *redisPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("redis plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = p.applyDocker(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("redis plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *redisPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "internal/cache/redis.go"); err != nil {
+		return err
+	}
+	requires := map[string]string{"github.com/redis/go-redis/v9": "v9.7.0"}
+	if ctx.Has("otel") {
+		requires["github.com/redis/go-redis/extra/redisotel/v9"] = "v9.7.0"
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, requires); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", "RedisURL string\nCacheTTL time.Duration"); err != nil {
+		return err
+	}
+	load := `cacheTTL, err := parseDuration(getenvDefault("CACHE_TTL", "5m"))
+if err != nil {
+	return Config{}, fmt.Errorf("CACHE_TTL: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", "RedisURL: getenvDefault(\"REDIS_URL\", \"redis://localhost:6379/0\"),\nCacheTTL: cacheTTL,"); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/cache")); err != nil {
+		return err
+	}
+	startup := `redisClient, err := cache.Connect(ctx, cfg)
+if err != nil {
+	slog.Error("redis connect failed", "err", err)
+	os.Exit(1)
+}
+defer redisClient.Close()
+`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:startup", startup); err != nil {
+		return err
+	}
+	health := `healthSvc.AddCheck("redis", func(ctx context.Context) error { return redisClient.Ping(ctx).Err() })`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:health", health); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, "REDIS_URL=redis://localhost:6379/0\nCACHE_TTL=5m\n")
+}
+
+func (p *redisPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"ioredis": "^5.4.1"}, false); err != nil {
+		return err
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `redis: {
+  url: process.env.REDIS_URL || "redis://localhost:6379/0",
+  cacheTtl: parseInt(process.env.CACHE_TTL || "300000", 10),
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { connectRedis, disconnectRedis } from "./cache/redis.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:startup", "await connectRedis();"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:shutdown", "await disconnectRedis();"); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, "REDIS_URL=redis://localhost:6379/0\nCACHE_TTL=300000\n")
+}
+
+func (p *redisPlugin) applyDocker(ctx *plugin.Context) error {
+	if !ctx.UseDocker {
+		return nil
+	}
+	if err := project.AddComposeService(ctx.TargetDir, "redis", redisService); err != nil {
+		return err
+	}
+	if err := project.AddComposeAppEnv(ctx.TargetDir, "REDIS_URL", "redis://redis:6379/0"); err != nil {
+		return err
+	}
+	return project.AddComposeAppDependsOn(ctx.TargetDir, "redis", true)
+}
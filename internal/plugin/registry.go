@@ -43,3 +43,34 @@ func CompatibleWith(stackKey string) []string {
 	}
 	return out
 }
+
+// Ordered returns the selected plugin names in the order they should be
+// applied: as given, except that a Dependent plugin comes after the
+// selected plugins it names.
+func Ordered(names []string) []string {
+	selected := make(map[string]bool, len(names))
+	for _, n := range names {
+		selected[n] = true
+	}
+	out := make([]string, 0, len(names))
+	visited := make(map[string]bool, len(names))
+	var visit func(string)
+	visit = func(n string) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+		if d, ok := Get(n).(Dependent); ok {
+			for _, dep := range d.After() {
+				if selected[dep] {
+					visit(dep)
+				}
+			}
+		}
+		out = append(out, n)
+	}
+	for _, n := range names {
+		visit(n)
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package securityheaders
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const envExample = `CORS_ALLOWED_ORIGINS=http://localhost:3000
+CORS_ALLOWED_METHODS=GET,POST,PUT,PATCH,DELETE,OPTIONS
+CORS_ALLOWED_HEADERS=Content-Type,Authorization,X-Request-Id
+CORS_ALLOW_CREDENTIALS=false
+HSTS_MAX_AGE=31536000
+CONTENT_SECURITY_POLICY="default-src 'none'; frame-ancestors 'none'"
+BODY_LIMIT_BYTES=1048576
+`
+
+type securityHeadersPlugin struct{}
+
+func init() {
+	plugin.Register(&securityHeadersPlugin{})
+}
+
+func (*securityHeadersPlugin) Name() string {
+	return "security-headers"
+}
+
+func (*securityHeadersPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+// After applies this plugin last: middleware injected later is mounted
+// earlier, so CORS and the headers also cover responses such as 401s and
+// 429s from the other plugins, and preflights are answered before any of
+// them run.
+func (*securityHeadersPlugin) After() []string {
+	return []string{"audit", "auth", "flags", "idempotency", "metrics", "otel", "ratelimit", "tenancy"}
+}
+
+func (p *securityHeadersPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("security-headers plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = project.AppendEnvExample(ctx.TargetDir, envExample)
+	}
+	if err != nil {
+		return fmt.Errorf("security-headers plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *securityHeadersPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	fields := `CORSAllowedOrigins string
+CORSAllowedMethods string
+CORSAllowedHeaders string
+CORSAllowCredentials bool
+HSTSMaxAge int
+ContentSecurityPolicy string
+BodyLimitBytes int64`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", fields); err != nil {
+		return err
+	}
+	load := `corsAllowCredentials, err := strconv.ParseBool(getenvDefault("CORS_ALLOW_CREDENTIALS", "false"))
+if err != nil {
+	return Config{}, fmt.Errorf("CORS_ALLOW_CREDENTIALS: %w", err)
+}
+hstsMaxAge, err := strconv.Atoi(getenvDefault("HSTS_MAX_AGE", "31536000"))
+if err != nil {
+	return Config{}, fmt.Errorf("HSTS_MAX_AGE: %w", err)
+}
+bodyLimit, err := strconv.ParseInt(getenvDefault("BODY_LIMIT_BYTES", "1048576"), 10, 64)
+if err != nil {
+	return Config{}, fmt.Errorf("BODY_LIMIT_BYTES: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	values := `CORSAllowedOrigins: getenvDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
+CORSAllowedMethods: getenvDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+CORSAllowedHeaders: getenvDefault("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-Request-Id"),
+CORSAllowCredentials: corsAllowCredentials,
+HSTSMaxAge: hstsMaxAge,
+ContentSecurityPolicy: getenvDefault("CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'"),
+BodyLimitBytes: bodyLimit,`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", values); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	return project.InjectAtMarker(mainGo, "// scaffold:middleware", "router.Use(middleware.CORS(cfg), middleware.SecureHeaders(cfg), middleware.BodyLimit(cfg.BodyLimitBytes))")
+}
+
+func (p *securityHeadersPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"cors": "^2.8.5", "helmet": "^8.0.0"}, false); err != nil {
+		return err
+	}
+	if ext == "ts" {
+		if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"@types/cors": "^2.8.17"}, true); err != nil {
+			return err
+		}
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `cors: {
+  origins: process.env.CORS_ALLOWED_ORIGINS || "http://localhost:3000",
+  methods: process.env.CORS_ALLOWED_METHODS || "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+  headers: process.env.CORS_ALLOWED_HEADERS || "Content-Type,Authorization,X-Request-Id",
+  credentials: process.env.CORS_ALLOW_CREDENTIALS === "true",
+},
+security: {
+  hstsMaxAge: parseInt(process.env.HSTS_MAX_AGE || "31536000", 10),
+  contentSecurityPolicy: process.env.CONTENT_SECURITY_POLICY ?? "default-src 'none'; frame-ancestors 'none'",
+  bodyLimit: parseInt(process.env.BODY_LIMIT_BYTES || "1048576", 10),
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.ReplaceInFile(server, "app.use(express.json());", "app.use(express.json({ limit: config.security.bodyLimit }));"); err != nil {
+		return err
+	}
+	if err := project.ReplaceInFile(server, "app.use(express.urlencoded({ extended: true }));", "app.use(express.urlencoded({ extended: true, limit: config.security.bodyLimit }));"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { corsMiddleware, securityHeadersMiddleware } from "./middleware/security.js";`); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:middleware", "app.use(corsMiddleware);\napp.use(securityHeadersMiddleware);")
+}
@@ -0,0 +1,79 @@
+package sse
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type ssePlugin struct{}
+
+func init() {
+	plugin.Register(&ssePlugin{})
+}
+
+func (*ssePlugin) Name() string {
+	return "sse"
+}
+
+func (*ssePlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *ssePlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("sse plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err != nil {
+		return fmt.Errorf("sse plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *ssePlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/sse")); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", "sseBroker := sse.NewBroker()\nrouter.GET(\"/events\", sseBroker.Handler)"); err != nil {
+		return err
+	}
+	// Open streams would otherwise hold srv.Shutdown until its timeout.
+	return project.InjectAtMarker(mainGo, "// scaffold:drain", "sseBroker.Close()")
+}
+
+func (p *ssePlugin) applyNode(ctx *plugin.Context, ext string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { closeSse, sseHandler } from "./sse/broker.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:routes", `app.get("/events", sseHandler);`); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:drain", "closeSse();")
+}
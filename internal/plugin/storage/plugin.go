@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+const minioService = `  minio:
+    image: minio/minio:RELEASE.2024-10-13T13-34-11Z
+    command: ["server", "/data", "--console-address", ":9001"]
+    environment:
+      MINIO_ROOT_USER: minioadmin
+      MINIO_ROOT_PASSWORD: minioadmin
+    ports:
+      - "9000:9000"
+      - "9001:9001"
+    volumes:
+      - minio_data:/data
+    healthcheck:
+      test: ["CMD", "mc", "ready", "local"]
+      interval: 5s
+      timeout: 3s
+      retries: 10
+`
+
+type storagePlugin struct{}
+
+func init() {
+	plugin.Register(&storagePlugin{})
+}
+
+func (*storagePlugin) Name() string {
+	return "storage"
+}
+
+func (*storagePlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *storagePlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("storage plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = p.applyDocker(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("storage plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *storagePlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, map[string]string{"github.com/minio/minio-go/v7": "v7.0.80"}); err != nil {
+		return err
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	fields := `StorageEndpoint string
+StoragePublicEndpoint string
+StorageRegion string
+StorageBucket string
+StorageAccessKey string
+StorageSecretKey string
+StoragePresignTTL time.Duration
+StorageMaxUploadBytes int64`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", fields); err != nil {
+		return err
+	}
+	load := `storageEndpoint := getenvDefault("STORAGE_ENDPOINT", "http://localhost:9000")
+storagePresignTTL, err := parseDuration(getenvDefault("STORAGE_PRESIGN_TTL", "15m"))
+if err != nil {
+	return Config{}, fmt.Errorf("STORAGE_PRESIGN_TTL: %w", err)
+}
+storageMaxUpload, err := strconv.ParseInt(getenvDefault("STORAGE_MAX_UPLOAD_BYTES", "10485760"), 10, 64)
+if err != nil {
+	return Config{}, fmt.Errorf("STORAGE_MAX_UPLOAD_BYTES: %w", err)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	values := `StorageEndpoint: storageEndpoint,
+StoragePublicEndpoint: getenvDefault("STORAGE_PUBLIC_ENDPOINT", storageEndpoint),
+StorageRegion: getenvDefault("STORAGE_REGION", "us-east-1"),
+StorageBucket: getenvDefault("STORAGE_BUCKET", "uploads"),
+StorageAccessKey: getenvDefault("STORAGE_ACCESS_KEY", "minioadmin"),
+StorageSecretKey: getenvDefault("STORAGE_SECRET_KEY", "minioadmin"),
+StoragePresignTTL: storagePresignTTL,
+StorageMaxUploadBytes: storageMaxUpload,`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", values); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/storage")); err != nil {
+		return err
+	}
+	startup := `objectStore, err := storage.New(ctx, cfg)
+if err != nil {
+	slog.Error("storage connect failed", "err", err)
+	os.Exit(1)
+}
+`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:startup", startup); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:health", `healthSvc.AddCheck("storage", objectStore.Ping)`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", "routes.RegisterFiles(router, handlers.NewFileHandler(objectStore, cfg.StorageMaxUploadBytes))"); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, envExample("15m"))
+}
+
+func (p *storagePlugin) applyNode(ctx *plugin.Context, ext string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{
+		"@aws-sdk/client-s3":            "^3.685.0",
+		"@aws-sdk/s3-request-presigner": "^3.685.0",
+		"multer":                        "^1.4.5-lts.1",
+	}, false); err != nil {
+		return err
+	}
+	if ext == "ts" {
+		if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"@types/multer": "^1.4.12"}, true); err != nil {
+			return err
+		}
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `storage: {
+  endpoint: process.env.STORAGE_ENDPOINT || "http://localhost:9000",
+  publicEndpoint: process.env.STORAGE_PUBLIC_ENDPOINT || process.env.STORAGE_ENDPOINT || "http://localhost:9000",
+  region: process.env.STORAGE_REGION || "us-east-1",
+  bucket: process.env.STORAGE_BUCKET || "uploads",
+  accessKey: process.env.STORAGE_ACCESS_KEY || "minioadmin",
+  secretKey: process.env.STORAGE_SECRET_KEY || "minioadmin",
+  presignTtl: parseInt(process.env.STORAGE_PRESIGN_TTL || "900000", 10),
+  maxUploadBytes: parseInt(process.env.STORAGE_MAX_UPLOAD_BYTES || "10485760", 10),
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	imports := `import { connectStorage, disconnectStorage } from "./storage/client.js";
+import filesRouter from "./routes/files.js";`
+	if err := project.InjectAtMarker(server, "// scaffold:imports", imports); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:startup", "await connectStorage();"); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:routes", `app.use("/files", filesRouter);`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:shutdown", "disconnectStorage();"); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, envExample("900000"))
+}
+
+func (p *storagePlugin) applyDocker(ctx *plugin.Context) error {
+	if !ctx.UseDocker {
+		return nil
+	}
+	if err := project.AddComposeService(ctx.TargetDir, "minio", minioService); err != nil {
+		return err
+	}
+	if err := project.AddComposeVolume(ctx.TargetDir, "minio_data"); err != nil {
+		return err
+	}
+	if err := project.AddComposeAppEnv(ctx.TargetDir, "STORAGE_ENDPOINT", "http://minio:9000"); err != nil {
+		return err
+	}
+	// Presigned URLs are used from the host, which reaches MinIO through
+	// the published port.
+	if err := project.AddComposeAppEnv(ctx.TargetDir, "STORAGE_PUBLIC_ENDPOINT", "http://localhost:9000"); err != nil {
+		return err
+	}
+	return project.AddComposeAppDependsOn(ctx.TargetDir, "minio", true)
+}
+
+func envExample(presignTTL string) string {
+	return `STORAGE_ENDPOINT=http://localhost:9000
+STORAGE_PUBLIC_ENDPOINT=http://localhost:9000
+STORAGE_REGION=us-east-1
+STORAGE_BUCKET=uploads
+STORAGE_ACCESS_KEY=minioadmin
+STORAGE_SECRET_KEY=minioadmin
+STORAGE_PRESIGN_TTL=` + presignTTL + `
+STORAGE_MAX_UPLOAD_BYTES=10485760
+`
+}
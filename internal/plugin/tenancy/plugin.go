@@ -0,0 +1,170 @@
+package tenancy
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type tenancyPlugin struct{}
+
+func init() {
+	plugin.Register(&tenancyPlugin{})
+}
+
+func (*tenancyPlugin) Name() string {
+	return "tenancy"
+}
+
+func (*tenancyPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *tenancyPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("tenancy plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err != nil {
+		return fmt.Errorf("tenancy plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *tenancyPlugin) applyGoGin(ctx *plugin.Context) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	configGo := filepath.Join(ctx.TargetDir, "config", "config.go")
+	fields := `TenancyStrategy string
+TenancyHeader string
+TenancyBaseDomain string`
+	if ctx.Has("auth") {
+		fields += "\nTenancyJWTClaim string"
+	}
+	fields += "\nTenancyExemptPaths string"
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-fields", fields); err != nil {
+		return err
+	}
+	jwtCase := ""
+	if ctx.Has("auth") {
+		jwtCase = `, "jwt"`
+	}
+	load := `tenancyStrategy := getenvDefault("TENANCY_STRATEGY", "header")
+switch tenancyStrategy {
+case "header"` + jwtCase + `:
+case "subdomain":
+	if os.Getenv("TENANCY_BASE_DOMAIN") == "" {
+		return Config{}, errors.New("TENANCY_BASE_DOMAIN is required for TENANCY_STRATEGY=subdomain")
+	}
+default:
+	return Config{}, fmt.Errorf("invalid TENANCY_STRATEGY %q (use: ` + strategies(ctx) + `)", tenancyStrategy)
+}
+`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-load", load); err != nil {
+		return err
+	}
+	values := `TenancyStrategy: tenancyStrategy,
+TenancyHeader: getenvDefault("TENANCY_HEADER", "X-Tenant-ID"),
+TenancyBaseDomain: os.Getenv("TENANCY_BASE_DOMAIN"),`
+	if ctx.Has("auth") {
+		values += `
+TenancyJWTClaim: getenvDefault("TENANCY_JWT_CLAIM", "tenant_id"),`
+	}
+	values += `
+TenancyExemptPaths: getenvDefault("TENANCY_EXEMPT_PATHS", "` + exemptPaths(ctx) + `"),`
+	if err := project.InjectAtMarker(configGo, "// scaffold:config-values", values); err != nil {
+		return err
+	}
+	if err := project.FormatGo(ctx.TargetDir, "config/config.go"); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/tenancy")); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:middleware", "router.Use(tenancy.Middleware(cfg))"); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, envExample(ctx))
+}
+
+func (p *tenancyPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	configFile := filepath.Join(ctx.TargetDir, "src", "config", "config."+ext)
+	block := `tenancy: {
+  strategy: process.env.TENANCY_STRATEGY || "header",
+  header: process.env.TENANCY_HEADER || "X-Tenant-ID",
+  baseDomain: process.env.TENANCY_BASE_DOMAIN || "",`
+	if ctx.Has("auth") {
+		block += `
+  jwtClaim: process.env.TENANCY_JWT_CLAIM || "tenant_id",`
+	}
+	block += `
+  exemptPaths: process.env.TENANCY_EXEMPT_PATHS || "` + exemptPaths(ctx) + `",
+},`
+	if err := project.InjectAtMarker(configFile, "// scaffold:config", block); err != nil {
+		return err
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { tenantMiddleware } from "./tenancy/tenancy.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:middleware", "app.use(tenantMiddleware);"); err != nil {
+		return err
+	}
+	return project.AppendEnvExample(ctx.TargetDir, envExample(ctx))
+}
+
+func strategies(ctx *plugin.Context) string {
+	if ctx.Has("auth") {
+		return "header|subdomain|jwt"
+	}
+	return "header|subdomain"
+}
+
+// exemptPaths are served without a tenant. With auth, logging in comes
+// before the client knows its tenant.
+func exemptPaths(ctx *plugin.Context) string {
+	if ctx.Has("auth") {
+		return "/health,/metrics,/auth/login"
+	}
+	return "/health,/metrics"
+}
+
+func envExample(ctx *plugin.Context) string {
+	env := `TENANCY_STRATEGY=header
+TENANCY_HEADER=X-Tenant-ID
+TENANCY_BASE_DOMAIN=
+`
+	if ctx.Has("auth") {
+		env += "TENANCY_JWT_CLAIM=tenant_id\n"
+	}
+	return env + "TENANCY_EXEMPT_PATHS=" + exemptPaths(ctx) + "\n"
+}
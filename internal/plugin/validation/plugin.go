@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type validationPlugin struct{}
+
+func init() {
+	plugin.Register(&validationPlugin{})
+}
+
+func (*validationPlugin) Name() string {
+	return "validation"
+}
+
+func (*validationPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *validationPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, map[string]string{"joi": "^17.13.3"})
+	case "node-express-ts":
+		err = p.applyNode(ctx, map[string]string{"zod": "^3.23.8"})
+	default:
+		return fmt.Errorf("validation plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err != nil {
+		return fmt.Errorf("validation plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *validationPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	// The versions gin itself depends on, required directly for the
+	// translations.
+	if err := project.AddGoRequires(ctx.TargetDir, map[string]string{
+		"github.com/go-playground/locales":              "v0.14.1",
+		"github.com/go-playground/universal-translator": "v0.18.1",
+		"github.com/go-playground/validator/v10":        "v10.20.0",
+	}); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/validation")); err != nil {
+		return err
+	}
+	setup := `if err := validation.Setup(); err != nil {
+	slog.Error("validation setup failed", "err", err)
+	os.Exit(1)
+}
+`
+	return project.InjectAtMarker(mainGo, "// scaffold:startup", setup)
+}
+
+func (p *validationPlugin) applyNode(ctx *plugin.Context, deps map[string]string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	return project.AddNPMDependencies(ctx.TargetDir, deps, false)
+}
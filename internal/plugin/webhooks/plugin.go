@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type webhooksPlugin struct{}
+
+func init() {
+	plugin.Register(&webhooksPlugin{})
+}
+
+func (*webhooksPlugin) Name() string {
+	return "webhooks"
+}
+
+func (*webhooksPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *webhooksPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("webhooks plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err == nil {
+		err = project.WriteErrorHelper(ctx.TargetDir, ctx.StackKey, ctx.ProjectName, ctx.Has("problems"))
+	}
+	if err == nil {
+		err = project.WriteMigration(ctx.TargetDir, ctx.Database, "create_webhooks", templatesFS)
+	}
+	if err != nil {
+		return fmt.Errorf("webhooks plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *webhooksPlugin) applyGoGin(ctx *plugin.Context) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/go-gin/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/webhooks")); err != nil {
+		return err
+	}
+	var setup string
+	switch ctx.Database {
+	case "postgresql":
+		setup = "webhookStore := webhooks.NewStore(dbPool)\n"
+	case "sqlite":
+		setup = "webhookStore := webhooks.NewStore(sqlDB)\n"
+	case "mongodb":
+		setup = `webhookStore, err := webhooks.NewStore(ctx, mongoClient.Database(cfg.MongoDBName))
+if err != nil {
+	slog.Error("webhook store setup failed", "err", err)
+	os.Exit(1)
+}
+`
+	}
+	setup += `webhookWorker := webhooks.NewWorker(webhookStore)
+webhookWorker.Start(ctx)
+routes.RegisterWebhooks(router, handlers.NewWebhookHandler(webhookStore))`
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", setup); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(mainGo, "// scaffold:drain", "webhookWorker.Wait(shutdownCtx)")
+}
+
+func (p *webhooksPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	for _, dir := range []string{"common", ctx.Database} {
+		if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey+"/"+dir, ctx.TargetDir, ctx); err != nil {
+			return err
+		}
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	imports := `import webhooksRouter from "./routes/webhooks.js";
+import { startWebhookWorker, stopWebhookWorker } from "./webhooks/worker.js";`
+	if ctx.Database == "mongodb" {
+		imports += "\n" + `import { ensureWebhookIndexes } from "./webhooks/store.js";`
+		if err := project.InjectAtMarker(server, "// scaffold:startup", "await ensureWebhookIndexes();"); err != nil {
+			return err
+		}
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:imports", imports); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:routes", `app.use("/webhooks", webhooksRouter);`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:server", "startWebhookWorker();"); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:drain", "await stopWebhookWorker();")
+}
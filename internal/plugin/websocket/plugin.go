@@ -0,0 +1,92 @@
+package websocket
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"project-scaffold/internal/plugin"
+	"project-scaffold/internal/project"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type websocketPlugin struct{}
+
+func init() {
+	plugin.Register(&websocketPlugin{})
+}
+
+func (*websocketPlugin) Name() string {
+	return "websocket"
+}
+
+func (*websocketPlugin) CompatibleStacks() []string {
+	return []string{"go-gin", "node-express", "node-express-ts"}
+}
+
+func (p *websocketPlugin) Apply(ctx *plugin.Context) error {
+	var err error
+	switch ctx.StackKey {
+	case "go-gin":
+		err = p.applyGoGin(ctx)
+	case "node-express":
+		err = p.applyNode(ctx, "js")
+	case "node-express-ts":
+		err = p.applyNode(ctx, "ts")
+	default:
+		return fmt.Errorf("websocket plugin: unsupported stack %q", ctx.StackKey)
+	}
+	if err != nil {
+		return fmt.Errorf("websocket plugin: %w", err)
+	}
+	return nil
+}
+
+func (p *websocketPlugin) applyGoGin(ctx *plugin.Context) error {
+	if err := project.WriteTemplates(templatesFS, "templates/go-gin", ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddGoRequires(ctx.TargetDir, map[string]string{"github.com/gorilla/websocket": "v1.5.3"}); err != nil {
+		return err
+	}
+
+	mainGo := filepath.Join(ctx.TargetDir, "cmd", "main.go")
+	if err := project.InjectAtMarker(mainGo, "// scaffold:imports", fmt.Sprintf("%q", ctx.ProjectName+"/internal/ws")); err != nil {
+		return err
+	}
+	route := `router.GET("/ws", wsHub.Handler)`
+	if ctx.Has("auth") {
+		route = `router.GET("/ws", ws.TokenFromQuery(), middleware.JWT(), wsHub.Handler)`
+	}
+	if err := project.InjectAtMarker(mainGo, "// scaffold:routes", "wsHub := ws.NewHub()\n"+route); err != nil {
+		return err
+	}
+	// Hijacked connections are not closed by srv.Shutdown.
+	return project.InjectAtMarker(mainGo, "// scaffold:drain", "wsHub.Close()")
+}
+
+func (p *websocketPlugin) applyNode(ctx *plugin.Context, ext string) error {
+	if err := project.WriteTemplates(templatesFS, "templates/"+ctx.StackKey, ctx.TargetDir, ctx); err != nil {
+		return err
+	}
+	if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"ws": "^8.18.0"}, false); err != nil {
+		return err
+	}
+	if ext == "ts" {
+		if err := project.AddNPMDependencies(ctx.TargetDir, map[string]string{"@types/ws": "^8.5.12"}, true); err != nil {
+			return err
+		}
+	}
+
+	server := filepath.Join(ctx.TargetDir, "src", "server."+ext)
+	if err := project.InjectAtMarker(server, "// scaffold:imports", `import { attachWebSocket, closeWebSocket } from "./ws/hub.js";`); err != nil {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:server", "attachWebSocket(server);"); err != nil {
+		return err
+	}
+	// Upgraded sockets would otherwise keep server.close() waiting.
+	return project.InjectAtMarker(server, "// scaffold:drain", "closeWebSocket();")
+}
@@ -0,0 +1,267 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The compose helpers edit the generated docker-compose.yml line by line.
+// They are no-ops when the project was generated without Docker files.
+
+const composeFile = "docker-compose.yml"
+
+func readCompose(targetDir string) ([]string, bool, error) {
+	b, err := os.ReadFile(filepath.Join(targetDir, composeFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return strings.Split(strings.TrimRight(string(b), "\n"), "\n"), true, nil
+}
+
+func writeCompose(targetDir string, lines []string) error {
+	return os.WriteFile(filepath.Join(targetDir, composeFile), []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
+
+// AddComposeService adds a service to docker-compose.yml. block is the
+// service definition indented as it should appear under "services:",
+// starting with "  <name>:". Existing services are not replaced.
+func AddComposeService(targetDir, name, block string) error {
+	lines, ok, err := readCompose(targetDir)
+	if err != nil || !ok {
+		return err
+	}
+	for _, l := range lines {
+		if l == "  "+name+":" {
+			return nil
+		}
+	}
+	insert := strings.Split(strings.TrimRight(block, "\n"), "\n")
+	at := len(lines)
+	for i, l := range lines {
+		if i > 0 && isTopLevel(l) && !strings.HasPrefix(l, "services:") {
+			at = i
+			break
+		}
+	}
+	// Keep one blank line between services.
+	for at > 0 && strings.TrimSpace(lines[at-1]) == "" {
+		at--
+	}
+	insert = append([]string{""}, insert...)
+	if at < len(lines) {
+		insert = append(insert, "")
+	}
+	lines = splice(lines, at, insert...)
+	return writeCompose(targetDir, dedupeBlankLines(lines))
+}
+
+// AddComposeVolume declares a named volume.
+func AddComposeVolume(targetDir, name string) error {
+	lines, ok, err := readCompose(targetDir)
+	if err != nil || !ok {
+		return err
+	}
+	for i, l := range lines {
+		if l != "volumes:" {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !isTopLevel(lines[end]) {
+			if strings.TrimSpace(lines[end]) == name+":" {
+				return nil
+			}
+			end++
+		}
+		for end > i+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		return writeCompose(targetDir, splice(lines, end, "  "+name+":"))
+	}
+	lines = append(lines, "", "volumes:", "  "+name+":")
+	return writeCompose(targetDir, lines)
+}
+
+// AddComposeAppEnv sets an environment variable on the app service.
+func AddComposeAppEnv(targetDir, key, value string) error {
+	return addToAppService(targetDir, "environment:", key+": "+value, key+":")
+}
+
+// AddComposeAppPort publishes another port of the app service, e.g.
+// "${GRPC_PORT:-9090}:9090".
+func AddComposeAppPort(targetDir, mapping string) error {
+	entry := "- " + strconv.Quote(mapping)
+	return addToAppService(targetDir, "ports:", entry, entry)
+}
+
+// AddComposeAppDependsOn makes the app service start after service, or
+// with healthy after its healthcheck passes. Short (list) depends_on
+// entries are rewritten to the long form when a condition is needed, and
+// an existing dependency on service is upgraded when healthy is set.
+func AddComposeAppDependsOn(targetDir, service string, healthy bool) error {
+	lines, ok, err := readCompose(targetDir)
+	if err != nil || !ok {
+		return err
+	}
+	start, end := serviceRange(lines, "app")
+	if start < 0 {
+		return nil
+	}
+	condition := "service_started"
+	if healthy {
+		condition = "service_healthy"
+	}
+	for i := start; i < end; i++ {
+		if lines[i] != "    depends_on:" {
+			continue
+		}
+		j := i + 1
+		longForm := false
+		var short []string
+		for ; j < end && strings.HasPrefix(lines[j], "      "); j++ {
+			entry := strings.TrimSpace(lines[j])
+			if entry == service+":" {
+				if healthy && j+1 < end && strings.TrimSpace(lines[j+1]) == "condition: service_started" {
+					lines[j+1] = "        condition: service_healthy"
+					return writeCompose(targetDir, lines)
+				}
+				return nil
+			}
+			if entry == "- "+service {
+				if !healthy {
+					return nil
+				}
+				// Rewritten below with the condition.
+				continue
+			}
+			if name, ok := strings.CutPrefix(entry, "- "); ok {
+				short = append(short, name)
+			} else {
+				longForm = true
+			}
+		}
+		switch {
+		case longForm:
+			return writeCompose(targetDir, splice(lines, j, "      "+service+":", "        condition: "+condition))
+		case !healthy:
+			return writeCompose(targetDir, splice(lines, j, "      - "+service))
+		}
+		// A condition needs the long form; existing entries keep waiting
+		// for their services to start.
+		var entries []string
+		for _, name := range short {
+			entries = append(entries, "      "+name+":", "        condition: service_started")
+		}
+		entries = append(entries, "      "+service+":", "        condition: "+condition)
+		out := append(append(append([]string{}, lines[:i+1]...), entries...), lines[j:]...)
+		return writeCompose(targetDir, out)
+	}
+	if healthy {
+		return writeCompose(targetDir, splice(lines, start+1, "    depends_on:", "      "+service+":", "        condition: "+condition))
+	}
+	return writeCompose(targetDir, splice(lines, start+1, "    depends_on:", "      - "+service))
+}
+
+// AddComposeServiceFromApp adds a service that runs the app's image with
+// the app's environment, volumes and dependencies, e.g. a background
+// worker. The app's published ports are dropped; settings (e.g.
+// `command: ["npm", "run", "worker"]`) are added under the service.
+func AddComposeServiceFromApp(targetDir, name string, settings ...string) error {
+	lines, ok, err := readCompose(targetDir)
+	if err != nil || !ok {
+		return err
+	}
+	start, end := serviceRange(lines, "app")
+	if start < 0 {
+		return nil
+	}
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	block := []string{"  " + name + ":"}
+	for i := start + 1; i < end; i++ {
+		if lines[i] == "    ports:" {
+			for i+1 < end && strings.HasPrefix(lines[i+1], "      ") {
+				i++
+			}
+			continue
+		}
+		block = append(block, lines[i])
+	}
+	for _, s := range settings {
+		block = append(block, "    "+s)
+	}
+	return AddComposeService(targetDir, name, strings.Join(block, "\n"))
+}
+
+// addToAppService adds entry to a map section (e.g. "environment:") of the
+// app service, creating the section when needed. exists is the prefix that
+// marks the entry as already present.
+func addToAppService(targetDir, section, entry, exists string) error {
+	lines, ok, err := readCompose(targetDir)
+	if err != nil || !ok {
+		return err
+	}
+	start, end := serviceRange(lines, "app")
+	if start < 0 {
+		return nil
+	}
+	for i := start; i < end; i++ {
+		if lines[i] != "    "+section {
+			continue
+		}
+		j := i + 1
+		for ; j < end && strings.HasPrefix(lines[j], "      "); j++ {
+			if strings.HasPrefix(strings.TrimSpace(lines[j]), exists) {
+				return nil
+			}
+		}
+		return writeCompose(targetDir, splice(lines, j, "      "+entry))
+	}
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return writeCompose(targetDir, splice(lines, end, "    "+section, "      "+entry))
+}
+
+// serviceRange returns the line range [start, end) of a service definition.
+func serviceRange(lines []string, name string) (int, int) {
+	for i, l := range lines {
+		if l != "  "+name+":" {
+			continue
+		}
+		j := i + 1
+		for j < len(lines) && (strings.TrimSpace(lines[j]) == "" || strings.HasPrefix(lines[j], "    ")) {
+			j++
+		}
+		return i, j
+	}
+	return -1, -1
+}
+
+func isTopLevel(line string) bool {
+	return line != "" && line[0] != ' ' && line[0] != '#'
+}
+
+func splice(lines []string, at int, insert ...string) []string {
+	out := make([]string, 0, len(lines)+len(insert))
+	out = append(out, lines[:at]...)
+	out = append(out, insert...)
+	return append(out, lines[at:]...)
+}
+
+func dedupeBlankLines(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for i, l := range lines {
+		if l == "" && i > 0 && lines[i-1] == "" {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
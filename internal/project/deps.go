@@ -0,0 +1,127 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AddGoRequires adds module requirements (path -> version) to the
+// project's go.mod. Modules that are already required are left alone;
+// `go mod tidy` fills in go.sum and indirect dependencies.
+func AddGoRequires(targetDir string, modules map[string]string) error {
+	path := filepath.Join(targetDir, "go.mod")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(b)
+
+	var add []string
+	for _, mod := range sortedKeys(modules) {
+		if strings.Contains(content, "\t"+mod+" ") || strings.Contains(content, "require "+mod+" ") {
+			continue
+		}
+		add = append(add, "\t"+mod+" "+modules[mod])
+	}
+	if len(add) == 0 {
+		return nil
+	}
+
+	start := strings.Index(content, "require (\n")
+	if start < 0 {
+		content = strings.TrimRight(content, "\n") + "\n\nrequire (\n" + strings.Join(add, "\n") + "\n)\n"
+		return os.WriteFile(path, []byte(content), 0o644)
+	}
+	end := strings.Index(content[start:], "\n)")
+	if end < 0 {
+		return fmt.Errorf("go.mod: unterminated require block")
+	}
+	end += start
+
+	lines := strings.Split(content[start+len("require (\n"):end], "\n")
+	lines = append(lines, add...)
+	sort.SliceStable(lines, func(i, j int) bool {
+		return strings.TrimSpace(lines[i]) < strings.TrimSpace(lines[j])
+	})
+	content = content[:start] + "require (\n" + strings.Join(lines, "\n") + content[end:]
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// AddNPMDependencies adds packages (name -> version range) to the
+// "dependencies" (or, with dev, "devDependencies") section of package.json,
+// keeping the file's formatting. Packages that are already listed are left
+// alone.
+func AddNPMDependencies(targetDir string, packages map[string]string, dev bool) error {
+	section := "dependencies"
+	if dev {
+		section = "devDependencies"
+	}
+	return addPackageJSONEntries(targetDir, section, packages)
+}
+
+// AddNPMScripts adds scripts (name -> command) to package.json. Existing
+// scripts are left alone.
+func AddNPMScripts(targetDir string, scripts map[string]string) error {
+	return addPackageJSONEntries(targetDir, "scripts", scripts)
+}
+
+func addPackageJSONEntries(targetDir, name string, add map[string]string) error {
+	path := filepath.Join(targetDir, "package.json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(b)
+
+	section := `"` + name + `": {`
+	start := strings.Index(content, section)
+	if start < 0 {
+		return fmt.Errorf("package.json: %q section not found", name)
+	}
+	bodyStart := start + len(section)
+	end := strings.Index(content[bodyStart:], "}")
+	if end < 0 {
+		return fmt.Errorf("package.json: unterminated %q section", name)
+	}
+	end += bodyStart
+
+	var entries []string
+	for _, line := range strings.Split(content[bodyStart:end], "\n") {
+		if line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ",")); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	changed := false
+	for _, key := range sortedKeys(add) {
+		if strings.Contains(content[bodyStart:end], `"`+key+`"`) {
+			continue
+		}
+		entries = append(entries, fmt.Sprintf("%q: %q", key, add[key]))
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	indent := "    "
+	closing := "  "
+	if i := strings.LastIndex(content[:start], "\n"); i >= 0 {
+		closing = content[i+1 : start]
+		indent = closing + "  "
+	}
+	body := "\n" + indent + strings.Join(entries, ",\n"+indent) + "\n" + closing
+	content = content[:bodyStart] + body + content[end:]
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,39 @@
+package project
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// WriteErrorHelper writes the helper the project's handlers and middleware
+// answer errors with, internal/respond on Go and src/errors/httpError.* on
+// Node, unless the project already has it. With problems it reports RFC
+// 7807 problems through the problems plugin, otherwise it answers with
+// {"error": ...} bodies.
+func WriteErrorHelper(targetDir, stack, module string, problems bool) error {
+	base := "templates/errors/" + stack
+	data := struct {
+		Module   string
+		Problems bool
+	}{module, problems}
+	return fs.WalkDir(templatesFS, base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(path, base+"/"), ".tmpl")
+		dst := filepath.Join(targetDir, filepath.FromSlash(rel))
+		if _, err := os.Stat(dst); err == nil {
+			return nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return WriteTemplate(templatesFS, path, dst, data)
+	})
+}
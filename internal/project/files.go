@@ -0,0 +1,179 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"project-scaffold/internal/naming"
+)
+
+// WriteTemplates renders every .tmpl file below base in fsys into targetDir,
+// keeping the relative layout and dropping the .tmpl suffix.
+func WriteTemplates(fsys fs.FS, base, targetDir string, data any) error {
+	return fs.WalkDir(fsys, base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !strings.HasSuffix(path, ".tmpl") {
+			return nil
+		}
+		rel := strings.TrimPrefix(path, base+"/")
+		rel = strings.TrimSuffix(rel, ".tmpl")
+		return WriteTemplate(fsys, path, filepath.Join(targetDir, filepath.FromSlash(rel)), data)
+	})
+}
+
+// WriteTemplate renders a single template file from fsys to dstPath.
+func WriteTemplate(fsys fs.FS, path, dstPath string, data any) error {
+	content, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return err
+	}
+	tpl, err := template.New(path).Funcs(Funcs).Parse(string(content))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dstPath, buf.Bytes(), 0o644)
+}
+
+// CopyFiles copies every file below base in fsys into targetDir unchanged.
+// It is for files that must not go through WriteTemplate, such as template
+// files of the generated project itself.
+func CopyFiles(fsys fs.FS, base, targetDir string) error {
+	return fs.WalkDir(fsys, base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(targetDir, filepath.FromSlash(strings.TrimPrefix(path, base+"/")))
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(dstPath, content, 0o644)
+	})
+}
+
+// InjectAtMarker inserts injection on the lines following markerLine, using
+// the marker's indentation. The marker itself is kept so later injections
+// can reuse it.
+func InjectAtMarker(filePath, markerLine, injection string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	var found bool
+	for i, line := range lines {
+		if strings.TrimSpace(line) != strings.TrimSpace(markerLine) {
+			continue
+		}
+		found = true
+		indent := ""
+		for _, c := range line {
+			if c == ' ' || c == '\t' {
+				indent += string(c)
+			} else {
+				break
+			}
+		}
+		injectLines := strings.Split(strings.TrimSuffix(injection, "\n"), "\n")
+		var newLines []string
+		for _, inj := range injectLines {
+			if inj != "" {
+				newLines = append(newLines, indent+inj)
+			}
+		}
+		rest := append([]string{line}, newLines...)
+		lines = append(lines[:i], append(rest, lines[i+1:]...)...)
+		break
+	}
+	if !found {
+		return fmt.Errorf("required marker %q not found in %s", markerLine, filePath)
+	}
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0o644)
+}
+
+// FormatGo runs gofmt over the given files, relative to targetDir.
+func FormatGo(targetDir string, files ...string) error {
+	for _, f := range files {
+		path := filepath.Join(targetDir, f)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		out, err := format.Source(src)
+		if err != nil {
+			return fmt.Errorf("format %s: %w", f, err)
+		}
+		if err := os.WriteFile(path, out, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceInFile replaces the first occurrence of old in the file at path. It
+// fails when old is not present so that template drift is noticed.
+func ReplaceInFile(path, old, new string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(b)
+	if !strings.Contains(content, old) {
+		return fmt.Errorf("%q not found in %s", old, path)
+	}
+	return os.WriteFile(path, []byte(strings.Replace(content, old, new, 1)), 0o644)
+}
+
+// FileContains reports whether the file at path contains s.
+func FileContains(path, s string) (bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(b), s), nil
+}
+
+func AppendEnvExample(targetDir, line string) error {
+	path := filepath.Join(targetDir, ".env.example")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(string(b), "\n") {
+		line = "\n" + line
+	}
+	return os.WriteFile(path, append(b, line...), 0o644)
+}
+
+// Funcs are the helpers available to every template rendered through
+// WriteTemplate.
+var Funcs = template.FuncMap{
+	"pascal": naming.Pascal,
+	"camel":  naming.Camel,
+	"snake":  naming.Snake,
+	"kebab":  naming.Kebab,
+	"plural": naming.Plural,
+	"lower":  strings.ToLower,
+	"inc":    func(i int) int { return i + 1 },
+}
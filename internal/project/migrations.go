@@ -0,0 +1,61 @@
+package project
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NextMigrationVersion returns the version for a new migration in dir: the
+// current UTC time as YYYYMMDDHHMMSS, or one past the newest version
+// already there when that is not earlier. Migrations written within the
+// same second thus still get distinct versions, in the order they were
+// written.
+func NextMigrationVersion(dir string) (string, error) {
+	next, _ := strconv.ParseUint(time.Now().UTC().Format("20060102150405"), 10, 64)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err == nil && v >= next {
+			next = v + 1
+		}
+	}
+	return strconv.FormatUint(next, 10), nil
+}
+
+// WriteMigration renders the up and down migrations for name (such as
+// "create_jobs") from templates/migrations/<database>/ in fsys into the
+// project's migrations/ directory. Databases without SQL migrations are
+// skipped, and so is a migration the project already has.
+func WriteMigration(targetDir, database, name string, fsys fs.FS) error {
+	if database != "postgresql" && database != "sqlite" {
+		return nil
+	}
+	dir := filepath.Join(targetDir, "migrations")
+	matches, err := filepath.Glob(filepath.Join(dir, "*_"+name+".up.sql"))
+	if err != nil || len(matches) > 0 {
+		return err
+	}
+	version, err := NextMigrationVersion(dir)
+	if err != nil {
+		return err
+	}
+	for _, direction := range []string{"up", "down"} {
+		src := "templates/migrations/" + database + "/" + name + "." + direction + ".sql.tmpl"
+		dst := filepath.Join(dir, version+"_"+name+"."+direction+".sql")
+		if err := WriteTemplate(fsys, src, dst, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
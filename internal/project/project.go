@@ -0,0 +1,68 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const MetaFile = ".scaffold.json"
+
+type Meta struct {
+	Stack    string   `json:"stack"`
+	Database string   `json:"database"`
+	Plugins  []string `json:"plugins"`
+	Broker   string   `json:"broker,omitempty"`
+}
+
+func (m Meta) HasPlugin(name string) bool {
+	for _, p := range m.Plugins {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+func WriteMeta(targetDir string, meta Meta) error {
+	b, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(targetDir, MetaFile), b, 0o644)
+}
+
+func ReadMeta(targetDir string) (Meta, error) {
+	b, err := os.ReadFile(filepath.Join(targetDir, MetaFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Meta{}, fmt.Errorf("%s not found in %s: run this command from a generated project", MetaFile, targetDir)
+	}
+	if err != nil {
+		return Meta{}, err
+	}
+	var meta Meta
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return Meta{}, fmt.Errorf("parse %s: %w", MetaFile, err)
+	}
+	if meta.Stack == "" || meta.Database == "" {
+		return Meta{}, fmt.Errorf("%s is missing stack or database", MetaFile)
+	}
+	return meta, nil
+}
+
+// GoModule returns the module path declared in the project's go.mod.
+func GoModule(targetDir string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(targetDir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if f := strings.Fields(line); len(f) == 2 && f[0] == "module" {
+			return f[1], nil
+		}
+	}
+	return "", fmt.Errorf("no module directive in %s", filepath.Join(targetDir, "go.mod"))
+}
@@ -0,0 +1,347 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	"project-scaffold/internal/naming"
+)
+
+// Field is one column / document property of a resource.
+type Field struct {
+	Name       string
+	GoName     string
+	Type       string
+	GoType     string
+	TSType     string
+	PGType     string
+	SQLiteType string
+	Binding    string
+	Nullable   bool
+	// Generated is set on integer primary keys, which the database assigns.
+	Generated bool
+}
+
+type fieldType struct {
+	goType, tsType, pgType, sqliteType, binding string
+}
+
+// types lists the field types accepted by "generate resource".
+var types = map[string]fieldType{
+	"string":  {"string", "string", "TEXT", "TEXT", ""},
+	"text":    {"string", "string", "TEXT", "TEXT", ""},
+	"int":     {"int", "number", "INTEGER", "INTEGER", ""},
+	"bigint":  {"int64", "number", "BIGINT", "INTEGER", ""},
+	"float":   {"float64", "number", "DOUBLE PRECISION", "REAL", ""},
+	"decimal": {"string", "string", "NUMERIC(20, 4)", "TEXT", "numeric"},
+	"bool":    {"bool", "boolean", "BOOLEAN", "INTEGER", ""},
+	"uuid":    {"string", "string", "UUID", "TEXT", "uuid"},
+	"time":    {"time.Time", "string", "TIMESTAMPTZ", "DATETIME", ""},
+	"date":    {"time.Time", "string", "DATE", "DATE", "datetime=2006-01-02"},
+}
+
+var typeAliases = map[string]string{
+	"str": "string", "integer": "int", "int64": "bigint", "long": "bigint",
+	"double": "float", "number": "float", "numeric": "decimal", "money": "decimal",
+	"boolean": "bool", "datetime": "time", "timestamp": "time", "timestamptz": "time",
+}
+
+// TypeNames returns the accepted field types for help output.
+func TypeNames() string {
+	return "string, text, int, bigint, float, decimal, bool, uuid, time, date"
+}
+
+// ParseField parses a "name:type" argument such as "total:decimal".
+func ParseField(arg string) (*Field, error) {
+	name, typ, ok := strings.Cut(arg, ":")
+	if !ok {
+		typ = "string"
+	}
+	name = naming.Snake(name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid field %q: missing name", arg)
+	}
+	return NewField(name, typ)
+}
+
+// NewField builds a field of the given type; name should already be in
+// column (snake_case) form.
+func NewField(name, typ string) (*Field, error) {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	if alias, ok := typeAliases[typ]; ok {
+		typ = alias
+	}
+	t, ok := types[typ]
+	if !ok {
+		return nil, fmt.Errorf("invalid type %q for field %q (use: %s)", typ, name, TypeNames())
+	}
+	return &Field{
+		Name:       name,
+		GoName:     naming.Pascal(name),
+		Type:       typ,
+		GoType:     t.goType,
+		TSType:     t.tsType,
+		PGType:     t.pgType,
+		SQLiteType: t.sqliteType,
+		Binding:    t.binding,
+	}, nil
+}
+
+// objectIDField is the _id of a MongoDB collection keyed by ObjectIds. It
+// is exposed as the hex string.
+func objectIDField() *Field {
+	return &Field{Name: "id", GoName: "ID", Type: "objectid", GoType: "string", TSType: "string"}
+}
+
+// IsObjectID reports whether the field holds a MongoDB ObjectId.
+func (f *Field) IsObjectID() bool {
+	return f.Type == "objectid"
+}
+
+// IsTime reports whether the Go type is time.Time.
+func (f *Field) IsTime() bool {
+	return f.GoType == "time.Time"
+}
+
+// GoFieldType is the Go type of the field; nullable columns use pointers.
+func (f *Field) GoFieldType() string {
+	if f.Nullable {
+		return "*" + f.GoType
+	}
+	return f.GoType
+}
+
+// IsRequiredNumber reports whether the field is a non-nullable int, bigint
+// or float. Its input field is a pointer, so that an explicit 0 passes the
+// required check and only a missing value fails it.
+func (f *Field) IsRequiredNumber() bool {
+	return !f.Nullable && (f.Type == "int" || f.Type == "bigint" || f.Type == "float")
+}
+
+// GoInputType is the Go type of the field in the create/update payload.
+// Decimals take a JSON number or a numeric string, dates a YYYY-MM-DD
+// string, as on Node.
+func (f *Field) GoInputType() string {
+	var t string
+	switch {
+	case f.IsRequiredNumber():
+		return "*" + f.GoType
+	case f.Type == "decimal":
+		t = "json.Number"
+	case f.Type == "date":
+		t = "string"
+	default:
+		return f.GoFieldType()
+	}
+	if f.Nullable {
+		return "*" + t
+	}
+	return t
+}
+
+// GoInputValue converts expr, the field of the create/update payload, to
+// the model's value.
+func (f *Field) GoInputValue(expr string) string {
+	switch {
+	case f.IsRequiredNumber():
+		return "*" + expr
+	case f.Type == "decimal" && f.Nullable:
+		return "decimalPtr(" + expr + ")"
+	case f.Type == "decimal":
+		return expr + ".String()"
+	case f.Type == "date" && f.Nullable:
+		return "parseDatePtr(" + expr + ")"
+	case f.Type == "date":
+		return "parseDate(" + expr + ")"
+	}
+	return expr
+}
+
+// GoTag returns the struct tags for the model struct.
+func (f *Field) GoTag(mongo bool) string {
+	if !mongo {
+		return fmt.Sprintf("`json:%q`", f.Name)
+	}
+	bsonName := f.Name
+	if f.Name == "id" {
+		bsonName = "_id"
+	}
+	return fmt.Sprintf("`json:%q bson:%q`", f.Name, bsonName)
+}
+
+// GoInputTag returns the struct tags for the create/update payload,
+// including gin binding rules.
+func (f *Field) GoInputTag() string {
+	var rules []string
+	switch {
+	case f.Nullable:
+		rules = append(rules, "omitempty")
+	case f.Type != "bool":
+		rules = append(rules, "required")
+	}
+	if f.Binding != "" {
+		rules = append(rules, f.Binding)
+	}
+	if len(rules) == 0 || (len(rules) == 1 && rules[0] == "omitempty") {
+		return fmt.Sprintf("`json:%q`", f.Name)
+	}
+	return fmt.Sprintf("`json:%q binding:%q`", f.Name, strings.Join(rules, ","))
+}
+
+// JSInvalid returns a JavaScript condition that is true when value is not
+// a valid input for the field.
+func (f *Field) JSInvalid(value string) string {
+	switch f.Type {
+	case "int", "bigint":
+		return fmt.Sprintf("!Number.isInteger(%s)", value)
+	case "float":
+		return fmt.Sprintf("typeof %s !== \"number\"", value)
+	case "decimal":
+		return fmt.Sprintf("!/^-?\\d+(\\.\\d+)?$/.test(String(%s))", value)
+	case "bool":
+		return fmt.Sprintf("typeof %s !== \"boolean\"", value)
+	case "uuid":
+		return fmt.Sprintf("typeof %s !== \"string\" || !/^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i.test(%s)", value, value)
+	case "time", "date":
+		return fmt.Sprintf("typeof %s !== \"string\" || Number.isNaN(Date.parse(%s))", value, value)
+	default:
+		return fmt.Sprintf("typeof %s !== \"string\"", value)
+	}
+}
+
+// JSExpect describes the expected input, for validation messages.
+func (f *Field) JSExpect() string {
+	switch f.Type {
+	case "int", "bigint":
+		return "an integer"
+	case "float":
+		return "a number"
+	case "decimal":
+		return "a decimal number"
+	case "bool":
+		return "a boolean"
+	case "uuid":
+		return "a UUID"
+	case "time":
+		return "an ISO 8601 timestamp"
+	case "date":
+		return "an ISO 8601 date"
+	default:
+		return "a string"
+	}
+}
+
+// ZodSchema is the zod schema of the field in request payloads, for
+// projects with the validation plugin.
+func (f *Field) ZodSchema() string {
+	var s string
+	switch f.Type {
+	case "int", "bigint":
+		s = "z.number().int()"
+	case "float":
+		s = "z.number()"
+	case "decimal":
+		s = `z.union([z.number(), z.string().regex(/^-?\d+(\.\d+)?$/)])`
+	case "bool":
+		s = "z.boolean()"
+	case "uuid":
+		s = "z.string().uuid()"
+	case "time":
+		s = "z.string().datetime({ offset: true })"
+	case "date":
+		s = "z.string().date()"
+	default:
+		s = "z.string()"
+	}
+	if f.Nullable {
+		s += ".nullish()"
+	}
+	return s
+}
+
+// JoiSchema is ZodSchema for JavaScript projects. Types are strict so that
+// "1" is not taken for a number, matching JSInvalid.
+func (f *Field) JoiSchema() string {
+	var s string
+	switch f.Type {
+	case "int", "bigint":
+		s = "Joi.number().integer().strict()"
+	case "float":
+		s = "Joi.number().strict()"
+	case "decimal":
+		s = `Joi.alternatives(Joi.number().strict(), Joi.string().pattern(/^-?\d+(\.\d+)?$/))`
+	case "bool":
+		s = "Joi.boolean().strict()"
+	case "uuid":
+		s = "Joi.string().guid()"
+	case "time":
+		s = "Joi.string().isoDate()"
+	case "date":
+		s = `Joi.string().pattern(/^\d{4}-\d{2}-\d{2}$/)`
+	default:
+		s = `Joi.string().allow("")`
+	}
+	if f.Nullable {
+		return s + ".allow(null)"
+	}
+	return s + ".required()"
+}
+
+// TSInputType is the TypeScript type accepted in request payloads.
+func (f *Field) TSInputType() string {
+	t := f.TSType
+	if f.IsTime() {
+		t = "string"
+	}
+	if f.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+// TSFieldType is the TypeScript type of a stored value. PostgreSQL and
+// MongoDB hand back Date objects for timestamps, SQLite keeps ISO strings.
+func (f *Field) TSFieldType(db string) string {
+	t := f.TSType
+	if f.IsTime() && db != "sqlite" {
+		t = "Date"
+	}
+	if f.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+func (f *Field) IsBool() bool {
+	return f.Type == "bool"
+}
+
+// JSStore converts a validated input value into its stored form.
+func (f *Field) JSStore(value, db string) string {
+	out := value
+	switch {
+	case f.Type == "decimal":
+		out = fmt.Sprintf("String(%s)", value)
+	case f.IsTime() && db != "sqlite":
+		out = fmt.Sprintf("new Date(%s)", value)
+	}
+	if f.Nullable && out != value {
+		return fmt.Sprintf("%s == null ? null : %s", value, out)
+	}
+	if f.Nullable {
+		return fmt.Sprintf("%s ?? null", value)
+	}
+	return out
+}
+
+// JSParam converts a stored value into a bind parameter. better-sqlite3
+// cannot bind booleans, so SQLite gets 0/1.
+func (f *Field) JSParam(value, db string) string {
+	if db != "sqlite" || !f.IsBool() {
+		return value
+	}
+	if f.Nullable {
+		return fmt.Sprintf("%s == null ? null : %s ? 1 : 0", value, value)
+	}
+	return fmt.Sprintf("%s ? 1 : 0", value)
+}
@@ -0,0 +1,207 @@
+package resource
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"project-scaffold/internal/project"
+)
+
+//go:embed all:templates
+var templatesFS embed.FS
+
+// Generate writes the model, repository, service, handler and routes for res
+// into the project at targetDir, adds a migration for SQL databases (unless
+// res is read-only, i.e. the table already exists) and registers the routes
+// with the server. An existing slice is only replaced
+// when force is set.
+func Generate(targetDir string, meta project.Meta, res *Resource, force bool) error {
+	res.Database = meta.Database
+	res.Validation = meta.HasPlugin("validation")
+	res.Problems = meta.HasPlugin("problems")
+	if err := res.Validate(); err != nil {
+		return fmt.Errorf("resource: %w", err)
+	}
+
+	var routesFile, serverFile string
+	switch meta.Stack {
+	case "go-gin":
+		mod, err := project.GoModule(targetDir)
+		if err != nil {
+			return err
+		}
+		res.Module = mod
+		routesFile = filepath.Join("internal", "routes", res.FileName+".go")
+		serverFile = filepath.Join("cmd", "main.go")
+	case "node-express":
+		routesFile = filepath.Join("src", "routes", res.PluralVar+".js")
+		serverFile = filepath.Join("src", "server.js")
+	case "node-express-ts":
+		routesFile = filepath.Join("src", "routes", res.PluralVar+".ts")
+		serverFile = filepath.Join("src", "server.ts")
+	default:
+		return fmt.Errorf("resource: unsupported stack %q", meta.Stack)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, routesFile)); err == nil && !force {
+		return fmt.Errorf("resource: %s already exists (use --force to regenerate)", routesFile)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	migration, err := migrationName(targetDir, res.Table)
+	if err != nil {
+		return err
+	}
+	rename := strings.NewReplacer(
+		"__name__", res.FileName,
+		"__var__", res.Var,
+		"__pluralVar__", res.PluralVar,
+		"__migration__", migration,
+	)
+
+	base := "templates/" + meta.Stack
+	var written []string
+	for _, dir := range []string{base + "/common", base + "/" + meta.Database} {
+		files, err := writeTree(dir, targetDir, res, rename, false)
+		if err != nil {
+			return fmt.Errorf("resource: %w", err)
+		}
+		written = append(written, files...)
+	}
+	files, err := writeTree(base+"/shared", targetDir, res, rename, true)
+	if err != nil {
+		return fmt.Errorf("resource: %w", err)
+	}
+	written = append(written, files...)
+	if meta.Database != "mongodb" && !res.ReadOnly {
+		files, err := writeTree("templates/migrations/"+meta.Database, filepath.Join(targetDir, "migrations"), res, rename, false)
+		if err != nil {
+			return fmt.Errorf("resource: %w", err)
+		}
+		written = append(written, files...)
+	}
+
+	if meta.Stack == "go-gin" {
+		var goFiles []string
+		for _, f := range written {
+			if strings.HasSuffix(f, ".go") {
+				goFiles = append(goFiles, f)
+			}
+		}
+		if err := project.FormatGo(targetDir, goFiles...); err != nil {
+			return fmt.Errorf("resource: %w", err)
+		}
+	}
+
+	if err := register(targetDir, serverFile, meta, res); err != nil {
+		return fmt.Errorf("resource: %w", err)
+	}
+	return nil
+}
+
+// writeTree renders the templates below base into targetDir, renaming path
+// tokens. With keepExisting, files that are already present are left alone;
+// they hold helpers shared by every resource. It returns the written paths
+// relative to targetDir.
+func writeTree(base, targetDir string, res *Resource, rename *strings.Replacer, keepExisting bool) ([]string, error) {
+	if _, err := fs.Stat(templatesFS, base); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	var written []string
+	err := fs.WalkDir(templatesFS, base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".tmpl") {
+			return err
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(path, base+"/"), ".tmpl")
+		rel = filepath.FromSlash(rename.Replace(rel))
+		dst := filepath.Join(targetDir, rel)
+		if keepExisting {
+			if _, err := os.Stat(dst); err == nil {
+				return nil
+			}
+		}
+		if err := project.WriteTemplate(templatesFS, path, dst, res); err != nil {
+			return err
+		}
+		written = append(written, rel)
+		return nil
+	})
+	return written, err
+}
+
+// migrationName reuses the name of an earlier migration for the same table
+// so regenerating does not stack up duplicates.
+func migrationName(targetDir, table string) (string, error) {
+	suffix := "_create_" + table + ".up.sql"
+	matches, err := filepath.Glob(filepath.Join(targetDir, "migrations", "*"+suffix))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) > 0 {
+		return strings.TrimSuffix(filepath.Base(matches[0]), ".up.sql"), nil
+	}
+	version, err := project.NextMigrationVersion(filepath.Join(targetDir, "migrations"))
+	if err != nil {
+		return "", err
+	}
+	return version + "_create_" + table, nil
+}
+
+// register wires the routes into the server entrypoint unless it already
+// mounts them.
+func register(targetDir, serverFile string, meta project.Meta, res *Resource) error {
+	server := filepath.Join(targetDir, serverFile)
+	if meta.Stack == "go-gin" {
+		done, err := project.FileContains(server, "routes.Register"+res.Plural+"(")
+		if err != nil || done {
+			return err
+		}
+		imported, err := project.FileContains(server, `"`+res.Module+`/internal/repository"`)
+		if err != nil {
+			return err
+		}
+		if !imported {
+			if err := project.InjectAtMarker(server, "// scaffold:imports", `"`+res.Module+`/internal/repository"`); err != nil {
+				return err
+			}
+		}
+		injection := fmt.Sprintf("%[1]sRepo := repository.New%[2]sRepository(%[3]s)\n"+
+			"%[1]sSvc := services.New%[2]sService(%[1]sRepo)\n"+
+			"routes.Register%[4]s(router, handlers.New%[2]sHandler(%[1]sSvc))\n",
+			res.GoVar, res.Name, goDBExpr(meta.Database), res.Plural)
+		if err := project.InjectAtMarker(server, "// scaffold:routes", injection); err != nil {
+			return err
+		}
+		return project.FormatGo(targetDir, serverFile)
+	}
+
+	router := res.PluralVar + "Router"
+	done, err := project.FileContains(server, router)
+	if err != nil || done {
+		return err
+	}
+	if err := project.InjectAtMarker(server, "// scaffold:imports",
+		fmt.Sprintf("import %s from \"./routes/%s.js\";", router, res.PluralVar)); err != nil {
+		return err
+	}
+	return project.InjectAtMarker(server, "// scaffold:routes",
+		fmt.Sprintf("app.use(%q, %s);", res.Path, router))
+}
+
+// goDBExpr is the expression main.go uses for the database handle.
+func goDBExpr(database string) string {
+	switch database {
+	case "mongodb":
+		return "mongoClient.Database(cfg.MongoDBName)"
+	case "sqlite":
+		return "sqlDB"
+	default:
+		return "dbPool"
+	}
+}
@@ -0,0 +1,197 @@
+package resource
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseJSONSchema reads MongoDB collection schemas. Accepted layouts are a
+// single schema (optionally wrapped in {"$jsonSchema": ...}, named by its
+// title or else defaultName), the output of db.getCollectionInfos(), or an
+// object mapping collection names to schemas.
+func ParseJSONSchema(b []byte, defaultName string) (*Schema, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("empty JSON schema")
+	}
+	doc := root.Content[0]
+	schema := &Schema{}
+
+	switch {
+	case doc.Kind == yaml.SequenceNode:
+		for _, info := range doc.Content {
+			name := scalar(mapValue(info, "name"))
+			s := collectionSchema(info)
+			if name == "" || s == nil {
+				schema.warnf("skipped collection entry without name or $jsonSchema validator")
+				continue
+			}
+			parseCollection(schema, name, s)
+		}
+	case doc.Kind != yaml.MappingNode:
+		return nil, fmt.Errorf("JSON schema must be an object or an array")
+	case collectionSchema(doc) != nil:
+		s := collectionSchema(doc)
+		name := scalar(mapValue(doc, "name"))
+		if name == "" {
+			name = scalar(mapValue(s, "title"))
+		}
+		if name == "" {
+			name = defaultName
+		}
+		parseCollection(schema, name, s)
+	default:
+		for i := 0; i+1 < len(doc.Content); i += 2 {
+			s := collectionSchema(doc.Content[i+1])
+			if s == nil {
+				schema.warnf("%s: skipped, not a JSON schema", doc.Content[i].Value)
+				continue
+			}
+			parseCollection(schema, doc.Content[i].Value, s)
+		}
+	}
+	return schema, nil
+}
+
+// collectionSchema unwraps {"options": {"validator": {"$jsonSchema": s}}}
+// and its inner forms; n itself counts when it declares properties.
+func collectionSchema(n *yaml.Node) *yaml.Node {
+	if o := mapValue(n, "options"); o != nil {
+		n = o
+	}
+	if v := mapValue(n, "validator"); v != nil {
+		n = v
+	}
+	if s := mapValue(n, "$jsonSchema"); s != nil {
+		return s
+	}
+	if mapValue(n, "properties") != nil {
+		return n
+	}
+	return nil
+}
+
+func parseCollection(schema *Schema, name string, s *yaml.Node) {
+	if !identRe.MatchString(name) {
+		schema.warnf("%s: skipped, collection name is not a plain identifier", name)
+		return
+	}
+	required := map[string]bool{}
+	if req := mapValue(s, "required"); req != nil {
+		for _, r := range req.Content {
+			required[r.Value] = true
+		}
+	}
+
+	// Collections without an explicit _id get MongoDB's default ObjectId.
+	id := objectIDField()
+	var fields []*Field
+	props := mapValue(s, "properties")
+	for i := 0; props != nil && i+1 < len(props.Content); i += 2 {
+		prop, def := props.Content[i].Value, props.Content[i+1]
+		typ, nullable := bsonType(def)
+		if prop == "_id" {
+			switch typ {
+			case "objectId":
+			case "string", "int", "integer", "long":
+				id, _ = NewField("id", jsonFieldType(typ, def))
+			default:
+				schema.warnf("%s: skipped, unsupported _id type %q", name, typ)
+				return
+			}
+			continue
+		}
+		fieldType := jsonFieldType(typ, def)
+		if fieldType == "" {
+			schema.warnf("%s.%s: skipped, unsupported type %q", name, prop, typ)
+			continue
+		}
+		f, err := newSchemaField(prop, fieldType, nullable || !required[prop])
+		if err != nil {
+			schema.warnf("%s.%s: skipped, %v", name, prop, err)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	schema.addResource(name, id, fields)
+}
+
+// bsonType returns the declared bsonType (or JSON type) of a property and
+// whether null is allowed.
+func bsonType(def *yaml.Node) (string, bool) {
+	t := mapValue(def, "bsonType")
+	if t == nil {
+		t = mapValue(def, "type")
+	}
+	if t == nil {
+		return "", true
+	}
+	if t.Kind == yaml.ScalarNode {
+		return t.Value, false
+	}
+	var typ string
+	nullable := false
+	for _, v := range t.Content {
+		if v.Value == "null" {
+			nullable = true
+		} else if typ == "" {
+			typ = v.Value
+		}
+	}
+	return typ, nullable
+}
+
+// jsonFieldType maps a BSON or JSON Schema type to a resource field type.
+// Embedded documents, arrays, binary data and Decimal128 (which the Go
+// driver cannot decode into a string) are not supported.
+func jsonFieldType(typ string, def *yaml.Node) string {
+	switch typ {
+	case "string":
+		switch scalar(mapValue(def, "format")) {
+		case "date-time":
+			return "time"
+		case "date":
+			return "date"
+		case "uuid":
+			return "uuid"
+		}
+		return "string"
+	case "objectId":
+		return "string"
+	case "int", "integer":
+		return "int"
+	case "long":
+		return "bigint"
+	case "double", "number":
+		return "float"
+	case "bool", "boolean":
+		return "bool"
+	case "date", "timestamp":
+		return "time"
+	default:
+		return ""
+	}
+}
+
+func mapValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalar(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
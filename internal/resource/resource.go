@@ -0,0 +1,268 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	"project-scaffold/internal/naming"
+)
+
+// Resource describes one model -> repository -> service -> handler -> route
+// slice. The same description drives "generate resource" and
+// "generate from-schema".
+type Resource struct {
+	Module   string
+	Database string
+
+	Name      string
+	Plural    string
+	Var       string
+	PluralVar string
+	GoVar     string
+	FileName  string
+	Table     string
+	Path      string
+
+	ID     *Field
+	Fields []*Field
+	// Timestamps adds created_at / updated_at, maintained by the service.
+	Timestamps bool
+	// ReadOnly resources only get list and get endpoints.
+	ReadOnly bool
+	// Validation is set when the validation plugin is installed.
+	Validation bool
+	// Problems is set when the problems plugin is installed; handlers then
+	// report apperrors instead of writing error responses.
+	Problems bool
+}
+
+// New builds a resource from a name and "name:type" field arguments. A
+// field called "id" becomes the primary key; without one a uuid id is added.
+func New(name string, fieldArgs []string) (*Resource, error) {
+	if naming.Pascal(name) == "" {
+		return nil, fmt.Errorf("invalid resource name %q", name)
+	}
+	r := newResource(name)
+	r.Timestamps = true
+
+	seen := map[string]bool{}
+	for _, arg := range fieldArgs {
+		f, err := ParseField(arg)
+		if err != nil {
+			return nil, err
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("duplicate field %q", f.Name)
+		}
+		seen[f.Name] = true
+		switch f.Name {
+		case "id":
+			r.ID = f
+			continue
+		case "created_at", "updated_at":
+			return nil, fmt.Errorf("field %q is added automatically", f.Name)
+		}
+		r.Fields = append(r.Fields, f)
+	}
+	if r.ID == nil {
+		r.ID, _ = NewField("id", "uuid")
+	}
+	switch r.ID.Type {
+	case "uuid":
+	case "int", "bigint":
+		r.ID.Generated = true
+	default:
+		return nil, fmt.Errorf("id must be uuid, int or bigint, got %q", r.ID.Type)
+	}
+	return r, nil
+}
+
+// FromTable builds a read-only resource over an existing table or
+// collection. The table name is used as is and taken to be the plural (so
+// "people" stays "people"); the resource is named after its singular form.
+func FromTable(table string, id *Field, fields []*Field) *Resource {
+	base := table
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		base = table[i+1:]
+	}
+	r := newResource(naming.Singular(base))
+	r.Plural = naming.Pascal(base)
+	r.PluralVar = naming.Camel(base)
+	r.Table = table
+	r.Path = "/" + naming.Kebab(r.Plural)
+	r.ID = id
+	r.Fields = fields
+	r.ReadOnly = true
+	return r
+}
+
+func newResource(name string) *Resource {
+	pascal := naming.Pascal(name)
+	return &Resource{
+		Name:      pascal,
+		Plural:    naming.Plural(pascal),
+		Var:       naming.Camel(name),
+		PluralVar: naming.Plural(naming.Camel(name)),
+		GoVar:     naming.GoCamel(name),
+		FileName:  naming.Snake(name),
+		Table:     naming.Plural(naming.Snake(name)),
+		Path:      "/" + naming.Plural(naming.Kebab(name)),
+	}
+}
+
+// Validate checks the resource against the project's database.
+func (r *Resource) Validate() error {
+	if r.Database == "mongodb" && r.ID.Generated {
+		return fmt.Errorf("mongodb resources need a uuid id, not %q", r.ID.Type)
+	}
+	return nil
+}
+
+func (r *Resource) timestampFields() []*Field {
+	if !r.Timestamps {
+		return nil
+	}
+	created, _ := NewField("created_at", "time")
+	updated, _ := NewField("updated_at", "time")
+	return []*Field{created, updated}
+}
+
+// All returns every stored field: id, the declared fields and timestamps.
+func (r *Resource) All() []*Field {
+	out := append([]*Field{r.ID}, r.Fields...)
+	return append(out, r.timestampFields()...)
+}
+
+// InsertFields are the fields written by INSERT; database-generated ids are
+// left out.
+func (r *Resource) InsertFields() []*Field {
+	if r.ID.Generated {
+		return r.All()[1:]
+	}
+	return r.All()
+}
+
+// UpdateFields are the fields written by UPDATE.
+func (r *Resource) UpdateFields() []*Field {
+	out := append([]*Field{}, r.Fields...)
+	if r.Timestamps {
+		out = append(out, r.timestampFields()[1])
+	}
+	return out
+}
+
+// OrderBy is the default sort for list queries.
+func (r *Resource) OrderBy() string {
+	if r.Timestamps {
+		return "created_at DESC"
+	}
+	return r.ID.Name
+}
+
+func (r *Resource) UsesTime() bool {
+	for _, f := range r.All() {
+		if f.IsTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBool reports whether any field is a boolean, which SQLite stores as 0/1.
+func (r *Resource) HasBool() bool {
+	for _, f := range r.All() {
+		if f.IsBool() {
+			return true
+		}
+	}
+	return false
+}
+
+// BoolColumns names the boolean fields.
+func (r *Resource) BoolColumns() []string {
+	var names []string
+	for _, f := range r.All() {
+		if f.IsBool() {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// InputUsesDecimal reports whether the create/update payload has a decimal,
+// which it takes as a json.Number.
+func (r *Resource) InputUsesDecimal() bool {
+	if r.ReadOnly {
+		return false
+	}
+	for _, f := range r.Fields {
+		if f.Type == "decimal" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resource) InputUsesTime() bool {
+	for _, f := range r.Fields {
+		if f.IsTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectColumns lists every stored column for SELECT statements.
+func (r *Resource) SelectColumns() string {
+	return columns(r.All())
+}
+
+func (r *Resource) InsertColumns() string {
+	return columns(r.InsertFields())
+}
+
+func (r *Resource) InsertPlaceholders() string {
+	return placeholders(r.Database, 1, len(r.InsertFields()))
+}
+
+// UpdateSet returns the SET clause; the id placeholder follows it
+// (see UpdateIDPlaceholder).
+func (r *Resource) UpdateSet() string {
+	parts := make([]string, len(r.UpdateFields()))
+	for i, f := range r.UpdateFields() {
+		parts[i] = f.Name + " = " + placeholder(r.Database, i+1)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r *Resource) UpdateIDPlaceholder() string {
+	return placeholder(r.Database, len(r.UpdateFields())+1)
+}
+
+// Placeholder returns the i-th (1-based) bind placeholder.
+func (r *Resource) Placeholder(i int) string {
+	return placeholder(r.Database, i)
+}
+
+func columns(fields []*Field) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func placeholders(db string, start, n int) string {
+	ph := make([]string, n)
+	for i := range ph {
+		ph[i] = placeholder(db, start+i)
+	}
+	return strings.Join(ph, ", ")
+}
+
+func placeholder(db string, i int) string {
+	if db == "postgresql" {
+		return fmt.Sprintf("$%d", i)
+	}
+	return "?"
+}
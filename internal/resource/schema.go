@@ -0,0 +1,96 @@
+package resource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Schema is the set of read-only resources found in an existing database
+// schema. Tables, collections and columns that cannot be mapped are skipped
+// and reported in Warnings.
+type Schema struct {
+	Resources []*Resource
+	Warnings  []string
+}
+
+func (s *Schema) warnf(format string, args ...any) {
+	s.Warnings = append(s.Warnings, fmt.Sprintf(format, args...))
+}
+
+// LoadSchema reads the schema file for the project's database: SQL DDL for
+// postgresql and sqlite, a JSON Schema for mongodb.
+func LoadSchema(path, database string) (*Schema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var schema *Schema
+	switch database {
+	case "postgresql", "sqlite":
+		schema, err = ParseSQL(string(b), database)
+	case "mongodb":
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		schema, err = ParseJSONSchema(b, name)
+	default:
+		return nil, fmt.Errorf("unsupported database %q", database)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(schema.Resources) == 0 {
+		return nil, fmt.Errorf("no usable tables found in %s", path)
+	}
+	return schema, nil
+}
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// newSchemaField builds a field for an existing column, keeping its name.
+func newSchemaField(name, typ string, nullable bool) (*Field, error) {
+	if !identRe.MatchString(name) {
+		return nil, fmt.Errorf("name %q is not a plain identifier", name)
+	}
+	f, err := NewField(name, typ)
+	if err != nil {
+		return nil, err
+	}
+	f.Nullable = nullable
+	return f, nil
+}
+
+// addResource checks the primary key and field names of a table before
+// adding it to the schema.
+func (s *Schema) addResource(table string, id *Field, fields []*Field) {
+	if id == nil {
+		s.warnf("%s: skipped, no single-column primary key", table)
+		return
+	}
+	switch id.Type {
+	case "uuid", "int", "bigint", "string", "text", "objectid":
+	default:
+		s.warnf("%s: skipped, primary key %s has unsupported type %s", table, id.Name, id.Type)
+		return
+	}
+	id.Nullable = false
+	seen := map[string]bool{id.GoName: true}
+	var kept []*Field
+	for _, f := range fields {
+		if seen[f.GoName] {
+			s.warnf("%s.%s: skipped, clashes with another column's Go name", table, f.Name)
+			continue
+		}
+		seen[f.GoName] = true
+		kept = append(kept, f)
+	}
+	res := FromTable(table, id, kept)
+	for _, other := range s.Resources {
+		if other.Name == res.Name {
+			s.warnf("%s: skipped, resource name %s is already used by %s", table, res.Name, other.Table)
+			return
+		}
+	}
+	s.Resources = append(s.Resources, res)
+}
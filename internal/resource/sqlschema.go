@@ -0,0 +1,282 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// sqlTypes maps SQL column types (lower-case, without length or precision)
+// to resource field types.
+var sqlTypes = map[string]string{
+	"text": "text", "varchar": "string", "character varying": "string", "char": "string",
+	"character": "string", "nvarchar": "string", "nchar": "string", "citext": "string",
+	"clob": "text", "json": "text", "jsonb": "text", "enum": "string",
+	"int": "int", "integer": "int", "int2": "int", "int4": "int", "smallint": "int",
+	"mediumint": "int", "tinyint": "int", "serial": "int", "smallserial": "int",
+	"serial4": "int", "bigint": "bigint", "int8": "bigint", "bigserial": "bigint", "serial8": "bigint",
+	"real": "float", "float": "float", "float4": "float", "float8": "float",
+	"double": "float", "double precision": "float",
+	"numeric": "decimal", "decimal": "decimal", "money": "decimal",
+	"bool": "bool", "boolean": "bool", "uuid": "uuid",
+	"timestamp": "time", "timestamptz": "time", "timestamp with time zone": "time",
+	"timestamp without time zone": "time", "datetime": "time",
+	"date": "date",
+}
+
+var createTableRe = regexp.MustCompile(`(?is)^CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+)?(?:UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?`)
+
+// columnKeywords end the type part of a column definition.
+var columnKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "PRIMARY": true, "DEFAULT": true, "REFERENCES": true,
+	"UNIQUE": true, "CHECK": true, "CONSTRAINT": true, "GENERATED": true, "COLLATE": true,
+	"AUTOINCREMENT": true, "AUTO_INCREMENT": true, "IDENTITY": true, "ON": true, "COMMENT": true,
+}
+
+// ParseSQL reads the CREATE TABLE statements of a SQL DDL script. Other
+// statements are ignored.
+func ParseSQL(src, database string) (*Schema, error) {
+	schema := &Schema{}
+	found := false
+	for _, stmt := range splitSQL(stripSQLComments(src), ';') {
+		stmt = strings.TrimSpace(stmt)
+		loc := createTableRe.FindStringIndex(stmt)
+		if loc == nil {
+			continue
+		}
+		found = true
+		rest := stmt[loc[1]:]
+		open := strings.Index(rest, "(")
+		if open < 0 {
+			schema.warnf("skipped statement without a column list: %.40s...", stmt)
+			continue
+		}
+		table, ok := sqlTableName(strings.TrimSpace(rest[:open]), database)
+		if !ok {
+			schema.warnf("%s: skipped, table name needs quoting", strings.TrimSpace(rest[:open]))
+			continue
+		}
+		body, ok := parenBody(rest[open:])
+		if !ok {
+			return nil, fmt.Errorf("%s: unbalanced parentheses", table)
+		}
+		parseCreateTable(schema, table, body, database)
+	}
+	if !found {
+		return nil, fmt.Errorf("no CREATE TABLE statements found")
+	}
+	return schema, nil
+}
+
+func parseCreateTable(schema *Schema, table, body, database string) {
+	var (
+		id     *Field
+		fields []*Field
+		pk     []string
+	)
+	for _, item := range splitSQL(body, ',') {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		words := strings.Fields(item)
+		keyword := strings.ToUpper(words[0])
+		if keyword == "KEY" || keyword == "INDEX" {
+			// MySQL index clauses, unless this is a column called key/index.
+			if typ, _ := splitColumnDef(item[len(words[0]):]); sqlTypes[typ] != "" {
+				keyword = ""
+			}
+		}
+		switch keyword {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "EXCLUDE":
+			upper := strings.ToUpper(item)
+			if i := strings.Index(upper, "PRIMARY KEY"); i >= 0 {
+				if cols, ok := parenBody(item[i+len("PRIMARY KEY"):]); ok {
+					pk = nil
+					for _, c := range strings.Split(cols, ",") {
+						if col := strings.Fields(c); len(col) > 0 {
+							pk = append(pk, unquoteIdent(col[0]))
+						}
+					}
+				}
+			}
+			continue
+		}
+
+		name := unquoteIdent(words[0])
+		typ, flags := splitColumnDef(item[len(words[0]):])
+		fieldType, ok := sqlTypes[typ]
+		if !ok {
+			schema.warnf("%s.%s: skipped, unsupported type %q", table, name, typ)
+			continue
+		}
+		f, err := newSchemaField(name, fieldType, !strings.Contains(flags, "NOT NULL"))
+		if err != nil {
+			schema.warnf("%s.%s: skipped, %v", table, name, err)
+			continue
+		}
+		if strings.Contains(flags, "PRIMARY KEY") {
+			pk = []string{name}
+		}
+		fields = append(fields, f)
+	}
+
+	if len(pk) == 1 {
+		for i, f := range fields {
+			if f.Name == pk[0] {
+				id = f
+				fields = append(fields[:i], fields[i+1:]...)
+				break
+			}
+		}
+	}
+	schema.addResource(table, id, fields)
+}
+
+// splitColumnDef separates the normalised type of a column definition from
+// its upper-cased constraint clauses.
+func splitColumnDef(def string) (typ, flags string) {
+	words := strings.Fields(def)
+	i := 0
+	var typeWords []string
+	for ; i < len(words); i++ {
+		if columnKeywords[strings.ToUpper(words[i])] {
+			break
+		}
+		typeWords = append(typeWords, words[i])
+	}
+	typ = strings.ToLower(strings.Join(typeWords, " "))
+	if p := strings.Index(typ, "("); p >= 0 {
+		if q := strings.LastIndex(typ, ")"); q > p {
+			typ = typ[:p] + typ[q+1:]
+		}
+	}
+	if strings.Contains(typ, "[") {
+		typ = "array"
+	}
+	typ = strings.Join(strings.Fields(typ), " ")
+	typ = strings.TrimSuffix(typ, " unsigned")
+	return typ, strings.ToUpper(strings.Join(words[i:], " "))
+}
+
+// sqlTableName unquotes a possibly schema-qualified table name. Quoted
+// PostgreSQL names with upper-case letters are rejected because the
+// generated queries use unquoted identifiers.
+func sqlTableName(raw, database string) (string, bool) {
+	parts := strings.Split(raw, ".")
+	for i, p := range parts {
+		name := unquoteIdent(p)
+		if !identRe.MatchString(name) {
+			return raw, false
+		}
+		if database == "postgresql" && name != p && name != strings.ToLower(name) {
+			return raw, false
+		}
+		parts[i] = name
+	}
+	return strings.Join(parts, "."), true
+}
+
+func unquoteIdent(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 {
+		switch {
+		case s[0] == '"' && s[len(s)-1] == '"',
+			s[0] == '`' && s[len(s)-1] == '`',
+			s[0] == '[' && s[len(s)-1] == ']':
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+// parenBody returns the text inside the first balanced pair of parentheses.
+func parenBody(s string) (string, bool) {
+	start := strings.Index(s, "(")
+	if start < 0 {
+		return "", false
+	}
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return s[start+1 : i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitSQL splits s on sep outside quotes and parentheses.
+func splitSQL(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// stripSQLComments removes -- and /* */ comments outside string literals.
+func stripSQLComments(s string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+			continue
+		}
+		if i < len(s) {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}